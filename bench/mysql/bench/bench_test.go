@@ -24,7 +24,7 @@ type Item struct {
     Payload   *string   `bun:"payload"`
 }
 
-func openDB(b *testing.B) *bun.DB {
+func openDB(b testing.TB) *bun.DB {
     dsn := os.Getenv("DSN")
     if dsn == "" {
         b.Fatal("DSN env not set")
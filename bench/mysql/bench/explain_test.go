@@ -0,0 +1,64 @@
+package bench
+
+import (
+    "context"
+    "fmt"
+    "os"
+    "testing"
+    "time"
+
+    "github.com/uptrace/bun"
+
+    pager "github.com/sky1core/proto-bun-page/pager"
+)
+
+// explain returns the first EXPLAIN row of q as strings keyed by column name.
+func explain(t *testing.T, db *bun.DB, q interface{}) map[string]string {
+    var rows []map[string]interface{}
+    if err := db.NewRaw("EXPLAIN ?", q).Scan(context.Background(), &rows); err != nil {
+        t.Fatal(err)
+    }
+    if len(rows) == 0 {
+        t.Fatal("empty EXPLAIN output")
+    }
+    out := make(map[string]string, len(rows[0]))
+    for k, v := range rows[0] {
+        if b, ok := v.([]byte); ok {
+            v = string(b)
+        }
+        if v != nil {
+            out[k] = fmt.Sprint(v)
+        }
+    }
+    return out
+}
+
+// Requires the bench stack (DSN) with sql/ applied: checks that the row-value cursor
+// predicate range-scans idx_items_created_desc_id_desc instead of scanning the table.
+func TestExplain_TupleCursorWhereUsesRangeScan(t *testing.T) {
+    if os.Getenv("DSN") == "" {
+        t.Skip("DSN env not set")
+    }
+    db := openDB(t)
+    defer db.Close()
+
+    plan := &pager.OrderPlan{Items: []pager.OrderItem{{Column: "created_at", Direction: "DESC"}, {Column: "id", Direction: "DESC"}}}
+    cd := &pager.CursorData{Values: map[string]interface{}{"created_at": time.Unix(1700002500, 0).UTC(), "id": int64(50000)}}
+    where, args, ok := pager.BuildTupleCursorWhere(cd, plan)
+    if !ok {
+        t.Fatal("expected a tuple predicate for a uniform DESC plan")
+    }
+    q := pager.ApplyOrderToQuery(db.NewSelect().Model((*Item)(nil)).Where(where, args...), plan).Limit(21)
+
+    row := explain(t, db, q)
+    if row["type"] != "range" || row["key"] != "idx_items_created_desc_id_desc" {
+        t.Fatalf("expected range scan on idx_items_created_desc_id_desc, got %v", row)
+    }
+
+    chainWhere, chainArgs, err := pager.BuildCursorWhere(cd, plan)
+    if err != nil {
+        t.Fatal(err)
+    }
+    chain := pager.ApplyOrderToQuery(db.NewSelect().Model((*Item)(nil)).Where(chainWhere, chainArgs...), plan).Limit(21)
+    t.Logf("OR-chain plan for comparison: %v", explain(t, db, chain))
+}
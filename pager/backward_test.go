@@ -0,0 +1,138 @@
+package pager
+
+import (
+    "context"
+    "testing"
+
+    pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+)
+
+func ids(rows []TestModel) []int64 {
+    out := make([]int64, len(rows))
+    for i, r := range rows { out[i] = r.ID }
+    return out
+}
+
+func sameIDs(a, b []int64) bool {
+    if len(a) != len(b) { return false }
+    for i := range a {
+        if a[i] != b[i] { return false }
+    }
+    return true
+}
+
+func TestApplyAndScan_PrevCursorReturnsPreviousPage(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error"})
+    order := []*pagerpb.Order{{Key: "score", Asc: false}, {Key: "name", Asc: true}}
+
+    var page1 []TestModel
+    out1, err := pg.ApplyAndScan(ctx, db.NewSelect().Model(&TestModel{}), &pagerpb.Page{Limit: 2, Order: order}, &page1)
+    if err != nil { t.Fatal(err) }
+    if out1.PrevCursor != "" { t.Fatal("first page must not have a previous cursor") }
+
+    var page2 []TestModel
+    out2, err := pg.ApplyAndScan(ctx, db.NewSelect().Model(&TestModel{}), &pagerpb.Page{
+        Limit: 2, Order: order, Selector: &pagerpb.Page_Cursor{Cursor: out1.GetCursor()},
+    }, &page2)
+    if err != nil { t.Fatal(err) }
+    if out2.PrevCursor == "" { t.Fatal("expected previous cursor on the second page") }
+
+    // Paging back from page 2 yields page 1 in request order
+    var back []TestModel
+    outBack, err := pg.ApplyAndScan(ctx, db.NewSelect().Model(&TestModel{}), &pagerpb.Page{
+        Limit: 2, Order: order, Direction: pagerpb.Direction_DIRECTION_BEFORE,
+        Selector: &pagerpb.Page_Cursor{Cursor: out2.PrevCursor},
+    }, &back)
+    if err != nil { t.Fatal(err) }
+    if !sameIDs(ids(back), ids(page1)) {
+        t.Fatalf("expected page 1 %v, got %v", ids(page1), ids(back))
+    }
+    if outBack.PrevCursor != "" { t.Fatal("nothing precedes page 1: previous cursor must be empty") }
+    if outBack.GetCursor() == "" { t.Fatal("expected a next cursor when paging backwards") }
+
+    // ...and its next cursor leads to page 2 again
+    var again []TestModel
+    if _, err := pg.ApplyAndScan(ctx, db.NewSelect().Model(&TestModel{}), &pagerpb.Page{
+        Limit: 2, Order: order, Selector: &pagerpb.Page_Cursor{Cursor: outBack.GetCursor()},
+    }, &again); err != nil { t.Fatal(err) }
+    if !sameIDs(ids(again), ids(page2)) {
+        t.Fatalf("expected page 2 %v, got %v", ids(page2), ids(again))
+    }
+}
+
+func TestApplyAndScan_EmptyCursorPage(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    pg := New(&Options{DefaultLimit: 4, MaxLimit: 10, LogLevel: "error"})
+    page := func(cursor string, dir pagerpb.Direction) ([]TestModel, *pagerpb.Page) {
+        var rows []TestModel
+        out, err := pg.ApplyAndScan(ctx, db.NewSelect().Model(&TestModel{}), &pagerpb.Page{Direction: dir, Selector: &pagerpb.Page_Cursor{Cursor: cursor}}, &rows)
+        if err != nil { t.Fatal(err) }
+        return rows, out
+    }
+
+    first, out1 := page("", pagerpb.Direction_DIRECTION_AFTER)
+    if !sameIDs(ids(first), []int64{5, 4, 3, 2}) { t.Fatalf("unexpected first page %v", ids(first)) }
+    // The only row after the cursor disappears: the next page is empty and, having no row
+    // to turn back from, claims no previous page it cannot link to
+    if _, err := db.NewDelete().Model((*TestModel)(nil)).Where("id = 1").Exec(ctx); err != nil { t.Fatal(err) }
+    empty, out2 := page(out1.GetCursor(), pagerpb.Direction_DIRECTION_AFTER)
+    info := out2.PageInfo
+    if len(empty) != 0 || info.HasPrevious || info.HasNext || out2.PrevCursor != "" || out2.GetCursor() != "" || info.StartCursor != "" {
+        t.Fatalf("unexpected empty page %v %+v (prev %q)", ids(empty), info, out2.PrevCursor)
+    }
+
+    // The same holds paging backwards past the first row
+    empty, out3 := page(out1.PageInfo.StartCursor, pagerpb.Direction_DIRECTION_BEFORE)
+    info = out3.PageInfo
+    if len(empty) != 0 || info.HasNext || info.HasPrevious || out3.GetCursor() != "" || out3.PrevCursor != "" {
+        t.Fatalf("unexpected empty backward page %v %+v", ids(empty), info)
+    }
+}
+
+func TestApplyAndScan_BackwardFullScanFromEnd(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error"})
+    order := []*pagerpb.Order{{Key: "created_at", Asc: false}}
+
+    var forward []TestModel
+    if _, err := pg.ApplyAndScan(ctx, db.NewSelect().Model(&TestModel{}), &pagerpb.Page{Limit: 10, Order: order}, &forward); err != nil {
+        t.Fatal(err)
+    }
+
+    // BEFORE with an empty cursor starts from the end; walk back to the start
+    var collected []TestModel
+    cursor := ""
+    for i := 0; i < 10; i++ {
+        var batch []TestModel
+        out, err := pg.ApplyAndScan(ctx, db.NewSelect().Model(&TestModel{}), &pagerpb.Page{
+            Limit: 2, Order: order, Direction: pagerpb.Direction_DIRECTION_BEFORE,
+            Selector: &pagerpb.Page_Cursor{Cursor: cursor},
+        }, &batch)
+        if err != nil { t.Fatal(err) }
+        if i == 0 && out.GetCursor() != "" { t.Fatal("last page must not have a next cursor") }
+        collected = append(batch, collected...)
+        if out.PrevCursor == "" { break }
+        cursor = out.PrevCursor
+    }
+    if !sameIDs(ids(collected), ids(forward)) {
+        t.Fatalf("backward scan %v does not match forward order %v", ids(collected), ids(forward))
+    }
+}
+
+func TestApplyAndScan_BackwardRequiresCursorMode(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error"})
+    var rows []TestModel
+    in := &pagerpb.Page{Limit: 2, Direction: pagerpb.Direction_DIRECTION_BEFORE, Selector: &pagerpb.Page_Page{Page: 2}}
+    if _, err := pg.ApplyAndScan(context.Background(), db.NewSelect().Model(&TestModel{}), in, &rows); err == nil {
+        t.Fatal("expected error for direction BEFORE in offset mode")
+    }
+}
@@ -0,0 +1,101 @@
+package pager
+
+import (
+    "context"
+    "testing"
+
+    pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+    "github.com/uptrace/bun"
+)
+
+// Association-style model keyed by (tenant_id, user_id).
+type membership struct {
+    TenantID  int64  `bun:"tenant_id,pk"`
+    UserID    int64  `bun:"user_id,pk"`
+    Role      string `bun:"role"`
+    CreatedAt int64  `bun:"created_at"`
+}
+
+func TestBuildOrderPlan_CompositePK_AppendsAllPKs(t *testing.T) {
+    info, err := InferModelInfo(&membership{})
+    if err != nil { t.Fatal(err) }
+
+    plan, err := BuildOrderPlan([]OrderSpecInterface{&pagerpb.Order{Key: "created_at", Asc: true}}, info, nil)
+    if err != nil { t.Fatal(err) }
+    want := []OrderItem{{Column: "created_at", Direction: "ASC"}, {Column: "tenant_id", Direction: "DESC"}, {Column: "user_id", Direction: "DESC"}}
+    if len(plan.Items) != len(want) { t.Fatalf("expected %d items, got %+v", len(want), plan.Items) }
+    for i, w := range want {
+        if plan.Items[i] != w { t.Fatalf("item %d: want %+v, got %+v", i, w, plan.Items[i]) }
+    }
+
+    // Only one PK column ordered explicitly: the other is still appended
+    plan, err = BuildOrderPlan([]OrderSpecInterface{&pagerpb.Order{Key: "user_id", Asc: true}}, info, nil)
+    if err != nil { t.Fatal(err) }
+    if len(plan.Items) != 2 || plan.Items[0].Column != "user_id" || plan.Items[1].Column != "tenant_id" {
+        t.Fatalf("expected [user_id tenant_id], got %+v", plan.Items)
+    }
+
+    // Empty key expands to the full PK tuple with the requested direction
+    plan, err = BuildOrderPlan([]OrderSpecInterface{&pagerpb.Order{Key: "", Asc: true}}, info, nil)
+    if err != nil { t.Fatal(err) }
+    if len(plan.Items) != 2 || plan.Items[0] != (OrderItem{Column: "tenant_id", Direction: "ASC"}) || plan.Items[1] != (OrderItem{Column: "user_id", Direction: "ASC"}) {
+        t.Fatalf("expected tenant_id ASC, user_id ASC, got %+v", plan.Items)
+    }
+}
+
+func TestCursor_CompositePK_RoundTrip(t *testing.T) {
+    info, err := InferModelInfo(&membership{})
+    if err != nil { t.Fatal(err) }
+    plan, err := BuildOrderPlan(nil, info, nil)
+    if err != nil { t.Fatal(err) }
+
+    token, err := EncodeCursor(plan, map[string]interface{}{"tenant_id": int64(7), "user_id": int64(42)}, info)
+    if err != nil { t.Fatal(err) }
+    cd, err := DecodeCursor(token, info)
+    if err != nil { t.Fatal(err) }
+    if cd.Values["tenant_id"] != int64(7) || cd.Values["user_id"] != int64(42) {
+        t.Fatalf("unexpected decoded values: %+v", cd.Values)
+    }
+
+    // A single-PK token cannot stand in for a composite tuple
+    single, err := EncodeCursor(plan, map[string]interface{}{"id": 1}, &ModelInfo{PKColumns: []string{"id"}})
+    if err != nil { t.Fatal(err) }
+    if _, err := DecodeCursor(single, info); err == nil {
+        t.Fatal("expected error decoding a non-tuple cursor for a composite PK")
+    }
+}
+
+func TestApplyAndScan_CompositePK_FullScan(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+
+    if _, err := db.NewCreateTable().Model((*membership)(nil)).Exec(ctx); err != nil { t.Fatal(err) }
+    var seed []membership
+    for tenant := int64(1); tenant <= 3; tenant++ {
+        for user := int64(1); user <= 4; user++ {
+            // created_at collides across tenants to force PK tiebreaking
+            seed = append(seed, membership{TenantID: tenant, UserID: user, Role: "member", CreatedAt: user * 100})
+        }
+    }
+    if _, err := db.NewInsert().Model(&seed).Exec(ctx); err != nil { t.Fatal(err) }
+
+    pg := New(&Options{DefaultLimit: 5, MaxLimit: 10, LogLevel: "error"})
+    type key struct{ tenant, user int64 }
+    seen := map[key]bool{}
+    var prev *membership
+    all := walkPages[membership](t, pg, func() *bun.SelectQuery { return db.NewSelect().Model(&membership{}) }, []*pagerpb.Order{{Key: "created_at", Asc: false}}, 5, false)
+    for i := range all {
+        r := all[i]
+        k := key{r.TenantID, r.UserID}
+        if seen[k] { t.Fatalf("duplicate row encountered: %+v", k) }
+        seen[k] = true
+        if prev != nil && (r.CreatedAt > prev.CreatedAt || (r.CreatedAt == prev.CreatedAt && r.TenantID > prev.TenantID)) {
+            t.Fatalf("order violated: %+v after %+v", r, *prev)
+        }
+        prev = &r
+    }
+    if len(seen) != len(seed) {
+        t.Fatalf("scanned %d rows but seeded %d", len(seen), len(seed))
+    }
+}
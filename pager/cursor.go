@@ -2,38 +2,45 @@ package pager
 
 import (
     "encoding/base64"
+    "encoding/json"
     "fmt"
     "math"
     "reflect"
     "strconv"
+    "time"
 )
 
 // CursorData represents decoded cursor values carried by a cursor token.
 type CursorData struct {
     Values map[string]interface{}
+    // SelfContained is true when Values cover every OrderPlan column, so the cursor WHERE
+    // is built without an anchor fetch. ApplyAndScan sets it once the order is known, and
+    // only with Options.SelfContainedCursor.
+    SelfContained bool
+    // Version is the cursor format version (0 for legacy PK-only tokens).
+    Version int
+    // OrderFingerprint identifies the OrderPlan the cursor was produced with ("" if unknown).
+    OrderFingerprint string
+    // Limit is the effective limit of the page that produced the cursor (0 if unknown).
+    Limit uint32
+    // QueryFingerprint is the hashed query fingerprint the cursor is bound to ("" if none).
+    QueryFingerprint string
 }
 
-// EncodeCursor creates a cursor string from row values
+// EncodeCursor creates a PK-only cursor string from row values: a versioned envelope
+// holding the typed PK tuple and the OrderPlan fingerprint. The anchor row is re-read
+// on the next page to recover the other order column values.
 func EncodeCursor(orderPlan *OrderPlan, row map[string]interface{}, modelInfo *ModelInfo) (string, error) {
-    cursorData := CursorData{Values: make(map[string]interface{})}
-
-    // Encode single primary key value for cursor token
-    pk := firstPKColumn(modelInfo)
-    if val, ok := row[pk]; ok {
-        cursorData.Values[pk] = val
-    }
-
-    // Build value-only payload in PK order: v1,v2,...
-    // Just single value string
-    s := ""
-    if v, ok := cursorData.Values[pk]; ok {
-        s = fmt.Sprint(v)
+    payload, err := marshalCursor(orderPlan, row, modelInfo, false, cursorHeader{})
+    if err != nil {
+        return "", err
     }
-    cursor := base64.URLEncoding.EncodeToString([]byte(s))
-    return cursor, nil
+    return base64.URLEncoding.EncodeToString(payload), nil
 }
 
-// DecodeCursor decodes a cursor string into values
+// DecodeCursor decodes a cursor string into values.
+// Envelopes of any supported version are accepted, as are legacy PK-only tokens
+// (a plain PK string, or a JSON array of strings for composite PKs).
 func DecodeCursor(cursor string, modelInfo *ModelInfo) (*CursorData, error) {
 	if cursor == "" {
 		return nil, nil
@@ -41,23 +48,102 @@ func DecodeCursor(cursor string, modelInfo *ModelInfo) (*CursorData, error) {
 
     decoded, err := base64.URLEncoding.DecodeString(cursor)
     if err != nil {
-        return nil, NewInvalidRequestError("invalid cursor format")
+        return nil, NewInvalidCursorError("invalid cursor format")
+    }
+    return unmarshalCursor(decoded, modelInfo)
+}
+
+// unmarshalCursor parses a raw (already decoded) payload: an envelope or a legacy PK-only token.
+func unmarshalCursor(decoded []byte, modelInfo *ModelInfo) (*CursorData, error) {
+    if cd, ok, err := decodeCursorEnvelope(decoded); ok {
+        if err != nil {
+            return nil, err
+        }
+        // Every envelope carries the full PK tuple, whichever mode produced it
+        for _, pk := range pkColumns(modelInfo) {
+            if _, ok := cd.Values[pk]; !ok {
+                return nil, NewInvalidCursorError("invalid cursor: missing pk")
+            }
+        }
+        // Typed values go into the cursor WHERE as is: they must fit their columns
+        for column, v := range cd.Values {
+            t, ok := modelInfo.ColumnTypes[column]
+            if !ok {
+                continue
+            }
+            if cd.Values[column], ok = cursorValueFor(v, t); !ok {
+                return nil, NewInvalidCursorError(fmt.Sprintf("invalid cursor: bad value for %q", column))
+            }
+        }
+        return cd, nil
     }
     s := string(decoded)
     cd := &CursorData{Values: map[string]interface{}{}}
     if len(s) == 0 {
         return cd, nil
     }
-    pk := firstPKColumn(modelInfo)
-    sv := s
-    if iv, err := strconv.ParseInt(sv, 10, 64); err == nil {
-        cd.Values[pk] = iv
-    } else {
-        cd.Values[pk] = sv
+    pks := pkColumns(modelInfo)
+    parts := []string{s}
+    if len(pks) > 1 {
+        parts = nil
+        if err := json.Unmarshal(decoded, &parts); err != nil || len(parts) != len(pks) {
+            return nil, NewInvalidCursorError("invalid cursor format")
+        }
+    }
+    for i, pk := range pks {
+        sv := parts[i]
+        if iv, err := strconv.ParseInt(sv, 10, 64); err == nil {
+            cd.Values[pk] = iv
+        } else {
+            cd.Values[pk] = sv
+        }
     }
     return cd, nil
 }
 
+// EncodeCursor creates a cursor token for row according to the pager options:
+// self-contained when SelfContainedCursor is set, PK-only otherwise, encoded with
+// CursorCodec (bound to the model table name) and signed when a CursorSigner is configured.
+func (p *Pager) EncodeCursor(orderPlan *OrderPlan, row map[string]interface{}, modelInfo *ModelInfo) (string, error) {
+    return p.encodeCursor(orderPlan, row, modelInfo, cursorHeader{})
+}
+
+func (p *Pager) encodeCursor(orderPlan *OrderPlan, row map[string]interface{}, modelInfo *ModelInfo, hdr cursorHeader) (string, error) {
+    payload, err := marshalCursor(orderPlan, row, modelInfo, p.opts.SelfContainedCursor, hdr)
+    if err != nil {
+        return "", err
+    }
+    token, err := p.cursorCodec().Encode(payload, cursorAAD(modelInfo))
+    if err != nil {
+        return "", err
+    }
+    if p.opts.CursorSigner != nil {
+        token = p.opts.CursorSigner.Sign(token)
+    }
+    return token, nil
+}
+
+// DecodeCursor decodes a token produced by EncodeCursor on a pager with the same options.
+// With a CursorSigner configured, unsigned or tampered tokens are rejected with INVALID_CURSOR_SIGNATURE;
+// an authenticating CursorCodec rejects tokens issued for another model the same way.
+func (p *Pager) DecodeCursor(cursor string, modelInfo *ModelInfo) (*CursorData, error) {
+    if cursor == "" {
+        return nil, nil
+    }
+    if p.opts.CursorSigner != nil {
+        token, err := p.opts.CursorSigner.Verify(cursor)
+        if err != nil {
+            return nil, err
+        }
+        cursor = token
+    }
+    payload, err := p.cursorCodec().Decode(cursor, cursorAAD(modelInfo))
+    if err != nil {
+        return nil, err
+    }
+    return unmarshalCursor(payload, modelInfo)
+}
+
 // ExtractRowValues extracts values from a row for cursor creation
 func ExtractRowValues(row interface{}, orderPlan *OrderPlan, modelInfo *ModelInfo) (map[string]interface{}, error) {
     values := make(map[string]interface{})
@@ -69,13 +155,92 @@ func ExtractRowValues(row interface{}, orderPlan *OrderPlan, modelInfo *ModelInf
     }
 
     for _, item := range orderPlan.Items {
-        if idx, ok := modelInfo.FieldIndexByColumn[item.Column]; ok {
-            values[item.Column] = v.Field(idx).Interface()
+        if val, ok := itemValue(v, item, modelInfo); ok {
+            values[item.Column] = val
         }
     }
     return values, nil
 }
 
+// itemValue reads the value of an order item from a struct row: the expression's Extract
+// result for expression items, the column value otherwise.
+func itemValue(v reflect.Value, item OrderItem, modelInfo *ModelInfo) (interface{}, bool) {
+    if item.Expr != nil {
+        return item.Expr.value(v), true
+    }
+    return columnValue(v, item.Column, modelInfo)
+}
+
+// columnValue reads a column from a struct row: a model field (possibly inside an embedded
+// struct), or a field of a joined relation. A nil embedded or relation pointer on the way
+// yields nil.
+func columnValue(v reflect.Value, column string, modelInfo *ModelInfo) (interface{}, bool) {
+    path, ok := modelInfo.FieldIndexByColumn[column]
+    if !ok {
+        path, ok = modelInfo.RelationFieldPaths[column]
+    }
+    if !ok {
+        return nil, false
+    }
+    f, err := v.FieldByIndexErr(path)
+    if err != nil {
+        return nil, true
+    }
+    return f.Interface(), true
+}
+
+// cursorValueFor coerces a decoded cursor value to a column's Go type the way the anchor
+// fetch does; ok is false when the value cannot be one (e.g. a string for an int column).
+// NULL is accepted for every column: relation and embedded pointer columns read as nil.
+func cursorValueFor(v interface{}, t reflect.Type) (interface{}, bool) {
+    t = filterValueType(t)
+    if v == nil || t == nil {
+        return v, true
+    }
+    switch t.Kind() {
+    case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+        if f, ok := v.(float64); ok && f != math.Trunc(f) {
+            return v, false
+        }
+        v = coerceToKind(v, reflect.Int64)
+        _, ok := v.(int64)
+        return v, ok
+    case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+        if f, ok := v.(float64); ok && f != math.Trunc(f) {
+            return v, false
+        }
+        v = coerceToKind(v, reflect.Uint64)
+        _, ok := v.(uint64)
+        return v, ok
+    case reflect.Float32, reflect.Float64:
+        switch nv := v.(type) {
+        case int64:
+            return float64(nv), true
+        case uint64:
+            return float64(nv), true
+        }
+        _, ok := v.(float64)
+        return v, ok
+    case reflect.String:
+        _, ok := v.(string)
+        return v, ok
+    case reflect.Bool:
+        _, ok := v.(bool)
+        return v, ok
+    case reflect.Slice:
+        if t.Elem().Kind() == reflect.Uint8 {
+            _, ok := v.([]byte)
+            return v, ok
+        }
+    case reflect.Struct:
+        if t == timeType {
+            _, ok := v.(time.Time)
+            return v, ok
+        }
+    }
+    return v, true
+}
+
 // coerceToKind converts v into a value assignable for the given reflect.Kind when reasonable.
 // For unsupported combinations, returns the original v.
 func coerceToKind(v interface{}, k reflect.Kind) interface{} {
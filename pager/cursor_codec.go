@@ -0,0 +1,92 @@
+package pager
+
+import (
+    "crypto/aes"
+    "crypto/cipher"
+    "crypto/rand"
+    "encoding/base64"
+    "errors"
+)
+
+// CursorCodec turns a raw cursor payload into the opaque token handed to clients and back.
+// aad is associated data the token is bound to (the model's table name); codecs that
+// authenticate must reject a token decoded with different aad.
+type CursorCodec interface {
+    Encode(payload, aad []byte) (string, error)
+    Decode(token string, aad []byte) ([]byte, error)
+}
+
+// base64CursorCodec is the default codec: URL-safe base64 of the payload, aad ignored.
+type base64CursorCodec struct{}
+
+func (base64CursorCodec) Encode(payload, _ []byte) (string, error) {
+    return base64.URLEncoding.EncodeToString(payload), nil
+}
+
+func (base64CursorCodec) Decode(token string, _ []byte) ([]byte, error) {
+    b, err := base64.URLEncoding.DecodeString(token)
+    if err != nil {
+        return nil, NewInvalidCursorError("invalid cursor format")
+    }
+    return b, nil
+}
+
+// aeadCursorCodec encrypts payloads so that cursors reveal nothing (e.g. sequential IDs) to clients.
+// Token layout: base64url(nonce || ciphertext).
+type aeadCursorCodec struct {
+    aead cipher.AEAD
+}
+
+// NewAESGCMCursorCodec returns an AES-GCM CursorCodec. key must be 16, 24 or 32 bytes.
+// The model table name is authenticated as associated data, so a cursor issued for one
+// resource fails to decode on another.
+func NewAESGCMCursorCodec(key []byte) (CursorCodec, error) {
+    block, err := aes.NewCipher(key)
+    if err != nil {
+        return nil, err
+    }
+    aead, err := cipher.NewGCM(block)
+    if err != nil {
+        return nil, err
+    }
+    return &aeadCursorCodec{aead: aead}, nil
+}
+
+func (c *aeadCursorCodec) Encode(payload, aad []byte) (string, error) {
+    nonce := make([]byte, c.aead.NonceSize(), c.aead.NonceSize()+len(payload)+c.aead.Overhead())
+    if _, err := rand.Read(nonce); err != nil {
+        return "", errors.New("cursor codec: nonce generation failed")
+    }
+    sealed := c.aead.Seal(nonce, nonce, payload, aad)
+    return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+func (c *aeadCursorCodec) Decode(token string, aad []byte) ([]byte, error) {
+    sealed, err := base64.RawURLEncoding.DecodeString(token)
+    if err != nil || len(sealed) < c.aead.NonceSize()+c.aead.Overhead() {
+        return nil, NewInvalidCursorError("invalid cursor format")
+    }
+    nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+    payload, err := c.aead.Open(nil, nonce, ciphertext, aad)
+    if err != nil {
+        // Tampered, foreign-key or cross-resource token
+        return nil, NewInvalidCursorSignatureError()
+    }
+    return payload, nil
+}
+
+// cursorCodec returns the configured codec or the base64 default.
+func (p *Pager) cursorCodec() CursorCodec {
+    if p.opts.CursorCodec != nil {
+        return p.opts.CursorCodec
+    }
+    return base64CursorCodec{}
+}
+
+// cursorAAD binds cursors to the model they were issued for.
+func cursorAAD(modelInfo *ModelInfo) []byte {
+    if modelInfo == nil {
+        return nil
+    }
+    return []byte(modelInfo.TableName)
+}
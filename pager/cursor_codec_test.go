@@ -0,0 +1,95 @@
+package pager
+
+import (
+    "context"
+    "strings"
+    "testing"
+
+    pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+    "github.com/uptrace/bun"
+)
+
+var testAESKey = []byte("0123456789abcdef0123456789abcdef")
+
+type taggedTableModel struct {
+    bun.BaseModel `bun:"table:accounts,alias:a"`
+    ID            int64 `bun:"id,pk"`
+}
+
+func TestInferModelInfo_TableName(t *testing.T) {
+    info, err := InferModelInfo(&TestModel{})
+    if err != nil { t.Fatal(err) }
+    if info.TableName != "test_models" {
+        t.Fatalf("expected bun default table name test_models, got %q", info.TableName)
+    }
+    info, err = InferModelInfo(&taggedTableModel{})
+    if err != nil { t.Fatal(err) }
+    if info.TableName != "accounts" {
+        t.Fatalf("expected table name from bun.BaseModel tag, got %q", info.TableName)
+    }
+    if _, ok := info.KeyToColumn["table:accounts"]; ok {
+        t.Fatal("bun.BaseModel tag must not be registered as a column")
+    }
+}
+
+func TestAESGCMCursorCodec_RoundTripAndAAD(t *testing.T) {
+    codec, err := NewAESGCMCursorCodec(testAESKey)
+    if err != nil { t.Fatal(err) }
+
+    token, err := codec.Encode([]byte("42"), []byte("users"))
+    if err != nil { t.Fatal(err) }
+    if strings.Contains(token, "NDI") { // base64("42") prefix
+        t.Fatal("token must not reveal the plaintext PK")
+    }
+    payload, err := codec.Decode(token, []byte("users"))
+    if err != nil { t.Fatal(err) }
+    if string(payload) != "42" { t.Fatalf("unexpected payload %q", payload) }
+
+    // Replay on another resource fails authentication
+    if _, err := codec.Decode(token, []byte("orders")); !isSignatureError(err) {
+        t.Fatalf("expected authentication failure for foreign table, got %v", err)
+    }
+    // Flipping a ciphertext character fails authentication
+    b := []byte(token)
+    if b[len(b)-1] == 'A' { b[len(b)-1] = 'B' } else { b[len(b)-1] = 'A' }
+    if _, err := codec.Decode(string(b), []byte("users")); err == nil {
+        t.Fatal("expected error for tampered ciphertext")
+    }
+
+    if _, err := NewAESGCMCursorCodec([]byte("short")); err == nil {
+        t.Fatal("expected error for invalid AES key size")
+    }
+}
+
+func TestApplyAndScan_EncryptedCursor(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    codec, err := NewAESGCMCursorCodec(testAESKey)
+    if err != nil { t.Fatal(err) }
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error", CursorCodec: codec})
+
+    in := &pagerpb.Page{Limit: 2, Order: []*pagerpb.Order{{Key: "created_at", Asc: false}}}
+    var first []TestModel
+    out, err := pg.ApplyAndScan(ctx, db.NewSelect().Model(&TestModel{}), in, &first)
+    if err != nil { t.Fatal(err) }
+    cursor := out.Selector.(*pagerpb.Page_Cursor).Cursor
+    if cursor == encodeTestCursor(t, first[len(first)-1].ID) {
+        t.Fatal("expected encrypted cursor, got plain base64 PK")
+    }
+
+    var next []TestModel
+    in2 := &pagerpb.Page{Limit: 2, Order: in.Order, Selector: &pagerpb.Page_Cursor{Cursor: cursor}}
+    if _, err := pg.ApplyAndScan(ctx, db.NewSelect().Model(&TestModel{}), in2, &next); err != nil { t.Fatal(err) }
+    if len(next) != 2 || next[0].CreatedAt >= first[len(first)-1].CreatedAt {
+        t.Fatalf("unexpected next page: %+v", next)
+    }
+
+    // The same cursor cannot be replayed against another model
+    if _, err := db.NewCreateTable().Model((*taggedTableModel)(nil)).Exec(ctx); err != nil { t.Fatal(err) }
+    var other []taggedTableModel
+    in3 := &pagerpb.Page{Limit: 2, Selector: &pagerpb.Page_Cursor{Cursor: cursor}}
+    if _, err := pg.ApplyAndScan(ctx, db.NewSelect().Model(&taggedTableModel{}), in3, &other); !isSignatureError(err) {
+        t.Fatalf("expected cross-resource replay to be rejected, got %v", err)
+    }
+}
@@ -0,0 +1,207 @@
+package pager
+
+import (
+    "database/sql/driver"
+    "encoding/base64"
+    "encoding/json"
+    "fmt"
+    "reflect"
+    "strconv"
+    "time"
+)
+
+// cursorFormatVersion is the envelope version written by this package.
+// Version 1 (implicit, no "ver" field) is the original self-contained payload;
+// PK-only tokens predating the envelope are plain strings and still decode.
+const cursorFormatVersion = 2
+
+// cursorEnvelope is the JSON body of a cursor token.
+//   ver: format version
+//   o:   OrderPlan fingerprint the cursor was produced with
+//   l:   effective limit of the page that produced the cursor
+//   q:   query fingerprint (hashed) the cursor is bound to; absent for unfiltered queries
+//   k/v: columns and their typed values at the same positions
+//        (PK columns plus relation order columns, or every OrderPlan column for self-contained cursors)
+type cursorEnvelope struct {
+    Version int           `json:"ver,omitempty"`
+    Order   string        `json:"o,omitempty"`
+    Limit   uint32        `json:"l,omitempty"`
+    Query   string        `json:"q,omitempty"`
+    Keys    []string      `json:"k"`
+    Values  []cursorValue `json:"v"`
+}
+
+// cursorHeader carries request context pinned into a cursor besides the row position.
+type cursorHeader struct {
+    Limit uint32
+    Query string
+}
+
+// cursorValue is a typed scalar. T is a one-letter type tag, V the string form.
+//   i=int64 u=uint64 f=float64 s=string b=bool t=time(RFC3339Nano) x=bytes(base64) n=NULL
+type cursorValue struct {
+    T string `json:"t"`
+    V string `json:"v,omitempty"`
+}
+
+// EncodeSelfContainedCursor creates a cursor carrying the value of every OrderPlan
+// column, so the next page can be resolved without re-reading the anchor row.
+// Values keep their type (int, uint, float, string, bool, time, bytes or NULL).
+func EncodeSelfContainedCursor(orderPlan *OrderPlan, row map[string]interface{}) (string, error) {
+    payload, err := marshalCursor(orderPlan, row, nil, true, cursorHeader{})
+    if err != nil {
+        return "", err
+    }
+    return base64.URLEncoding.EncodeToString(payload), nil
+}
+
+// marshalCursor builds a versioned envelope. With selfContained the values of every
+// OrderPlan column are stored; otherwise the PK tuple plus any relation columns, which
+// the anchor fetch cannot read from the model table.
+func marshalCursor(orderPlan *OrderPlan, row map[string]interface{}, modelInfo *ModelInfo, selfContained bool, hdr cursorHeader) ([]byte, error) {
+    env := cursorEnvelope{Version: cursorFormatVersion, Order: orderPlan.Fingerprint(), Limit: hdr.Limit, Query: hdr.Query}
+    for _, column := range cursorColumns(orderPlan, modelInfo, selfContained) {
+        val, ok := row[column]
+        if !ok {
+            return nil, fmt.Errorf("missing column %q in row values", column)
+        }
+        cv, err := encodeCursorValue(val)
+        if err != nil {
+            return nil, fmt.Errorf("column %q: %w", column, err)
+        }
+        env.Keys = append(env.Keys, column)
+        env.Values = append(env.Values, cv)
+    }
+    return json.Marshal(env)
+}
+
+// cursorColumns lists the columns a cursor stores: every OrderPlan column when
+// selfContained, otherwise the PK tuple plus any relation columns.
+func cursorColumns(orderPlan *OrderPlan, modelInfo *ModelInfo, selfContained bool) []string {
+    var columns []string
+    if selfContained {
+        for _, item := range orderPlan.Items {
+            columns = append(columns, item.Column)
+        }
+        return columns
+    }
+    columns = append(columns, pkColumns(modelInfo)...)
+    if orderPlan != nil {
+        for _, item := range orderPlan.Items {
+            if _, ok := item.relationAlias(); ok {
+                columns = append(columns, item.Column)
+            }
+        }
+    }
+    return columns
+}
+
+// decodeCursorEnvelope parses an envelope. ok is false when the bytes are not a JSON
+// object with a "ver" or (version 1) "k" member, i.e. a legacy PK-only token such as a
+// string PK starting with '{'; malformed envelopes are INVALID_CURSOR.
+func decodeCursorEnvelope(b []byte) (cd *CursorData, ok bool, err error) {
+    var members map[string]json.RawMessage
+    if len(b) == 0 || b[0] != '{' || json.Unmarshal(b, &members) != nil {
+        return nil, false, nil
+    }
+    if _, ok := members["ver"]; !ok {
+        if _, ok := members["k"]; !ok {
+            return nil, false, nil
+        }
+    }
+    var env cursorEnvelope
+    if err := json.Unmarshal(b, &env); err != nil {
+        return nil, true, NewInvalidCursorError("invalid cursor format")
+    }
+    if env.Version == 0 {
+        env.Version = 1
+    }
+    if env.Version > cursorFormatVersion {
+        return nil, true, NewInvalidCursorError(fmt.Sprintf("unsupported cursor version %d", env.Version))
+    }
+    if len(env.Keys) == 0 || len(env.Keys) != len(env.Values) {
+        return nil, true, NewInvalidCursorError("invalid cursor format")
+    }
+    cd = &CursorData{
+        Values:           make(map[string]interface{}, len(env.Keys)),
+        Version:          env.Version,
+        OrderFingerprint: env.Order,
+        Limit:            env.Limit,
+        QueryFingerprint: env.Query,
+    }
+    for i, k := range env.Keys {
+        v, err := decodeCursorValue(env.Values[i])
+        if err != nil {
+            return nil, true, NewInvalidCursorError("invalid cursor format")
+        }
+        cd.Values[k] = v
+    }
+    return cd, true, nil
+}
+
+func encodeCursorValue(v interface{}) (cursorValue, error) {
+    if vr, ok := v.(driver.Valuer); ok {
+        // sql.Null*, custom scanner types: use their driver representation
+        rv := reflect.ValueOf(v)
+        if rv.Kind() == reflect.Ptr && rv.IsNil() {
+            return cursorValue{T: "n"}, nil
+        }
+        dv, err := vr.Value()
+        if err != nil {
+            return cursorValue{}, err
+        }
+        v = dv
+    }
+    if v == nil {
+        return cursorValue{T: "n"}, nil
+    }
+    if t, ok := v.(time.Time); ok {
+        return cursorValue{T: "t", V: t.Format(time.RFC3339Nano)}, nil
+    }
+    if b, ok := v.([]byte); ok {
+        return cursorValue{T: "x", V: base64.StdEncoding.EncodeToString(b)}, nil
+    }
+
+    rv := reflect.ValueOf(v)
+    if rv.Kind() == reflect.Ptr {
+        if rv.IsNil() {
+            return cursorValue{T: "n"}, nil
+        }
+        return encodeCursorValue(rv.Elem().Interface())
+    }
+    switch rv.Kind() {
+    case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+        return cursorValue{T: "i", V: strconv.FormatInt(rv.Int(), 10)}, nil
+    case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+        return cursorValue{T: "u", V: strconv.FormatUint(rv.Uint(), 10)}, nil
+    case reflect.Float32, reflect.Float64:
+        return cursorValue{T: "f", V: strconv.FormatFloat(rv.Float(), 'g', -1, 64)}, nil
+    case reflect.String:
+        return cursorValue{T: "s", V: rv.String()}, nil
+    case reflect.Bool:
+        return cursorValue{T: "b", V: strconv.FormatBool(rv.Bool())}, nil
+    }
+    return cursorValue{}, fmt.Errorf("unsupported cursor value type %T", v)
+}
+
+func decodeCursorValue(cv cursorValue) (interface{}, error) {
+    switch cv.T {
+    case "n":
+        return nil, nil
+    case "i":
+        return strconv.ParseInt(cv.V, 10, 64)
+    case "u":
+        return strconv.ParseUint(cv.V, 10, 64)
+    case "f":
+        return strconv.ParseFloat(cv.V, 64)
+    case "s":
+        return cv.V, nil
+    case "b":
+        return strconv.ParseBool(cv.V)
+    case "t":
+        return time.Parse(time.RFC3339Nano, cv.V)
+    case "x":
+        return base64.StdEncoding.DecodeString(cv.V)
+    }
+    return nil, fmt.Errorf("unknown cursor value type %q", cv.T)
+}
@@ -0,0 +1,121 @@
+package pager
+
+import (
+    "context"
+    "encoding/base64"
+    "testing"
+
+    pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+)
+
+func pagerErrorCode(err error) string {
+    if pe, ok := err.(*PagerError); ok {
+        return pe.Code
+    }
+    return ""
+}
+
+func TestOrderPlan_Fingerprint(t *testing.T) {
+    a := &OrderPlan{Items: []OrderItem{{Column: "created_at", Direction: "DESC"}, {Column: "id", Direction: "DESC"}}}
+    b := &OrderPlan{Items: []OrderItem{{Column: "created_at", Direction: "DESC"}, {Column: "id", Direction: "DESC"}}}
+    c := &OrderPlan{Items: []OrderItem{{Column: "created_at", Direction: "ASC"}, {Column: "id", Direction: "DESC"}}}
+    if a.Fingerprint() == "" || a.Fingerprint() != b.Fingerprint() {
+        t.Fatal("expected equal plans to share a non-empty fingerprint")
+    }
+    if a.Fingerprint() == c.Fingerprint() {
+        t.Fatal("expected a direction change to alter the fingerprint")
+    }
+}
+
+type codeModel struct {
+    Code string `bun:"code,pk"`
+}
+
+func TestDecodeCursor_LegacyBraceStringPK(t *testing.T) {
+    info, err := InferModelInfo(&codeModel{})
+    if err != nil { t.Fatal(err) }
+    // Legacy tokens of string PKs that merely look like JSON objects are not envelopes
+    for _, pk := range []string{`{abc}`, `{}`, `{"k":`, `{"name":"x"}`} {
+        cd, err := DecodeCursor(base64.URLEncoding.EncodeToString([]byte(pk)), info)
+        if err != nil { t.Fatalf("%s: %v", pk, err) }
+        if cd.Values["code"] != pk || cd.Version != 0 {
+            t.Fatalf("%s: unexpected legacy decode %+v", pk, cd)
+        }
+    }
+}
+
+func TestApplyAndScan_CursorOrderMismatch(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error"})
+
+    var rows []TestModel
+    out, err := pg.ApplyAndScan(ctx, db.NewSelect().Model(&TestModel{}), &pagerpb.Page{Limit: 2, Order: []*pagerpb.Order{{Key: "created_at", Asc: false}}}, &rows)
+    if err != nil { t.Fatal(err) }
+    cursor := out.Selector.(*pagerpb.Page_Cursor).Cursor
+
+    // Same key, flipped direction: the cursor's anchor semantics no longer apply
+    in2 := &pagerpb.Page{Limit: 2, Order: []*pagerpb.Order{{Key: "created_at", Asc: true}}, Selector: &pagerpb.Page_Cursor{Cursor: cursor}}
+    _, err = pg.ApplyAndScan(ctx, db.NewSelect().Model(&TestModel{}), in2, &rows)
+    if pagerErrorCode(err) != ErrCodeCursorOrderMismatch {
+        t.Fatalf("expected CURSOR_ORDER_MISMATCH, got %v", err)
+    }
+}
+
+func TestApplyAndScan_CursorCarriesLimit(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    pg := New(&Options{DefaultLimit: 4, MaxLimit: 10, LogLevel: "error"})
+
+    var rows []TestModel
+    out, err := pg.ApplyAndScan(ctx, db.NewSelect().Model(&TestModel{}), &pagerpb.Page{Limit: 1}, &rows)
+    if err != nil { t.Fatal(err) }
+    cursor := out.Selector.(*pagerpb.Page_Cursor).Cursor
+
+    // No limit on the follow-up request: the cursor's limit (1) applies instead of DefaultLimit (4)
+    var next []TestModel
+    out2, err := pg.ApplyAndScan(ctx, db.NewSelect().Model(&TestModel{}), &pagerpb.Page{Selector: &pagerpb.Page_Cursor{Cursor: cursor}}, &next)
+    if err != nil { t.Fatal(err) }
+    if len(next) != 1 || out2.Limit != 1 {
+        t.Fatalf("expected limit 1 carried by the cursor, got %d rows (limit %d)", len(next), out2.Limit)
+    }
+}
+
+func TestDecodeCursor_Versions(t *testing.T) {
+    info, err := InferModelInfo(&TestModel{})
+    if err != nil { t.Fatal(err) }
+
+    // Legacy PK-only token (plain base64 of the PK) is still accepted
+    cd, err := DecodeCursor(base64.URLEncoding.EncodeToString([]byte("3")), info)
+    if err != nil { t.Fatal(err) }
+    if cd.Values["id"] != int64(3) || cd.Version != 0 || cd.OrderFingerprint != "" {
+        t.Fatalf("unexpected legacy decode: %+v", cd)
+    }
+
+    // Current envelope carries version and order fingerprint
+    plan, err := BuildOrderPlan(nil, info, nil)
+    if err != nil { t.Fatal(err) }
+    token, err := EncodeCursor(plan, map[string]interface{}{"id": int64(3)}, info)
+    if err != nil { t.Fatal(err) }
+    cd, err = DecodeCursor(token, info)
+    if err != nil { t.Fatal(err) }
+    if cd.Version != cursorFormatVersion || cd.OrderFingerprint != plan.Fingerprint() || cd.Values["id"] != int64(3) {
+        t.Fatalf("unexpected envelope decode: %+v", cd)
+    }
+
+    // Envelopes from a newer format are rejected explicitly
+    future := base64.URLEncoding.EncodeToString([]byte(`{"ver":99,"k":["id"],"v":[{"t":"i","v":"3"}]}`))
+    if _, err := DecodeCursor(future, info); pagerErrorCode(err) != ErrCodeInvalidCursor {
+        t.Fatalf("expected INVALID_CURSOR for unsupported version, got %v", err)
+    }
+
+    // Malformed envelopes are not mistaken for legacy PK tokens
+    for _, body := range []string{`{"ver":2}`, `{"k":[],"v":[]}`, `{"k":["id"],"v":[]}`, `{"k":"id","v":[{"t":"i","v":"3"}]}`, `{"ver":"2","k":["id"],"v":[{"t":"i","v":"3"}]}`} {
+        token := base64.URLEncoding.EncodeToString([]byte(body))
+        if _, err := DecodeCursor(token, info); pagerErrorCode(err) != ErrCodeInvalidCursor {
+            t.Fatalf("%s: expected INVALID_CURSOR, got %v", body, err)
+        }
+    }
+}
@@ -0,0 +1,73 @@
+package pager
+
+import (
+    "crypto/hmac"
+    "crypto/sha256"
+    "encoding/base64"
+    "errors"
+    "strings"
+)
+
+// CursorSigner signs cursor tokens with HMAC-SHA256.
+// Signed tokens have the form "<payload>.<keyID>.<mac>". New tokens are always
+// signed with the active key; every key in the keyring is accepted on verify,
+// so keys can be rotated without invalidating cursors already handed out.
+type CursorSigner struct {
+    activeKeyID string
+    keys        map[string][]byte
+}
+
+// NewCursorSigner builds a signer over a keyring (key ID -> secret).
+// activeKeyID selects the key used for signing and must be present in keys.
+func NewCursorSigner(activeKeyID string, keys map[string][]byte) (*CursorSigner, error) {
+    if len(keys) == 0 {
+        return nil, errors.New("cursor signer: empty keyring")
+    }
+    ring := make(map[string][]byte, len(keys))
+    for id, k := range keys {
+        if id == "" || strings.Contains(id, ".") {
+            return nil, errors.New("cursor signer: key ID must be non-empty and must not contain '.'")
+        }
+        if len(k) < 16 {
+            return nil, errors.New("cursor signer: key " + id + " is shorter than 16 bytes")
+        }
+        ring[id] = append([]byte(nil), k...)
+    }
+    if _, ok := ring[activeKeyID]; !ok {
+        return nil, errors.New("cursor signer: active key " + activeKeyID + " not in keyring")
+    }
+    return &CursorSigner{activeKeyID: activeKeyID, keys: ring}, nil
+}
+
+// Sign appends the active key ID and MAC to token.
+func (s *CursorSigner) Sign(token string) string {
+    mac := s.mac(s.keys[s.activeKeyID], s.activeKeyID, token)
+    return token + "." + s.activeKeyID + "." + mac
+}
+
+// Verify checks a signed token and returns the inner token.
+// Unsigned, tampered or unknown-key tokens yield INVALID_CURSOR_SIGNATURE.
+func (s *CursorSigner) Verify(signed string) (string, error) {
+    parts := strings.Split(signed, ".")
+    if len(parts) != 3 {
+        return "", NewInvalidCursorSignatureError()
+    }
+    token, keyID, mac := parts[0], parts[1], parts[2]
+    key, ok := s.keys[keyID]
+    if !ok {
+        return "", NewInvalidCursorSignatureError()
+    }
+    if !hmac.Equal([]byte(mac), []byte(s.mac(key, keyID, token))) {
+        return "", NewInvalidCursorSignatureError()
+    }
+    return token, nil
+}
+
+func (s *CursorSigner) mac(key []byte, keyID, token string) string {
+    h := hmac.New(sha256.New, key)
+    // Key ID is covered by the MAC so it cannot be swapped independently
+    h.Write([]byte(keyID))
+    h.Write([]byte{'.'})
+    h.Write([]byte(token))
+    return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,108 @@
+package pager
+
+import (
+    "context"
+    "strings"
+    "testing"
+
+    pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+)
+
+var (
+    testKeyV1 = []byte("0123456789abcdef-v1")
+    testKeyV2 = []byte("0123456789abcdef-v2")
+)
+
+func isSignatureError(err error) bool {
+    pe, ok := err.(*PagerError)
+    return ok && pe.Code == ErrCodeInvalidCursorSignature
+}
+
+func TestCursorSigner_SignVerifyAndTamper(t *testing.T) {
+    s, err := NewCursorSigner("v1", map[string][]byte{"v1": testKeyV1})
+    if err != nil { t.Fatal(err) }
+
+    token := encodeTestCursor(t, 42)
+    signed := s.Sign(token)
+    got, err := s.Verify(signed)
+    if err != nil { t.Fatal(err) }
+    if got != token { t.Fatalf("expected inner token %q, got %q", token, got) }
+
+    forged := encodeTestCursor(t, 43) + signed[len(token):]
+    if _, err := s.Verify(forged); !isSignatureError(err) {
+        t.Fatalf("expected signature error for swapped payload, got %v", err)
+    }
+    if _, err := s.Verify(token); !isSignatureError(err) {
+        t.Fatalf("expected signature error for unsigned token, got %v", err)
+    }
+    if _, err := s.Verify(strings.Replace(signed, ".v1.", ".v9.", 1)); !isSignatureError(err) {
+        t.Fatalf("expected signature error for unknown key ID, got %v", err)
+    }
+}
+
+func TestCursorSigner_KeyRotation(t *testing.T) {
+    old, err := NewCursorSigner("v1", map[string][]byte{"v1": testKeyV1})
+    if err != nil { t.Fatal(err) }
+    rotated, err := NewCursorSigner("v2", map[string][]byte{"v1": testKeyV1, "v2": testKeyV2})
+    if err != nil { t.Fatal(err) }
+
+    token := encodeTestCursor(t, 7)
+    // Tokens issued before rotation still verify
+    if _, err := rotated.Verify(old.Sign(token)); err != nil {
+        t.Fatalf("expected old-key token to verify after rotation: %v", err)
+    }
+    // New tokens are signed with the active key
+    if !strings.Contains(rotated.Sign(token), ".v2.") {
+        t.Fatal("expected new tokens to carry the active key ID")
+    }
+    // Once v1 is retired, its tokens are rejected
+    retired, err := NewCursorSigner("v2", map[string][]byte{"v2": testKeyV2})
+    if err != nil { t.Fatal(err) }
+    if _, err := retired.Verify(old.Sign(token)); !isSignatureError(err) {
+        t.Fatalf("expected retired-key token to be rejected, got %v", err)
+    }
+}
+
+func TestNewCursorSigner_Validation(t *testing.T) {
+    if _, err := NewCursorSigner("v1", nil); err == nil { t.Fatal("expected error for empty keyring") }
+    if _, err := NewCursorSigner("v2", map[string][]byte{"v1": testKeyV1}); err == nil { t.Fatal("expected error for missing active key") }
+    if _, err := NewCursorSigner("v1", map[string][]byte{"v1": []byte("short")}); err == nil { t.Fatal("expected error for short key") }
+    if _, err := NewCursorSigner("a.b", map[string][]byte{"a.b": testKeyV1}); err == nil { t.Fatal("expected error for key ID containing '.'") }
+}
+
+func TestApplyAndScan_SignedCursor(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    signer, err := NewCursorSigner("v1", map[string][]byte{"v1": testKeyV1})
+    if err != nil { t.Fatal(err) }
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error", CursorSigner: signer})
+
+    in := &pagerpb.Page{Limit: 2, Order: []*pagerpb.Order{{Key: "created_at", Asc: false}}}
+    var rows []TestModel
+    out, err := pg.ApplyAndScan(ctx, db.NewSelect().Model(&TestModel{}), in, &rows)
+    if err != nil { t.Fatal(err) }
+    cursor := out.Selector.(*pagerpb.Page_Cursor).Cursor
+
+    // Genuine cursor continues
+    var next []TestModel
+    in2 := &pagerpb.Page{Limit: 2, Order: in.Order, Selector: &pagerpb.Page_Cursor{Cursor: cursor}}
+    if _, err := pg.ApplyAndScan(ctx, db.NewSelect().Model(&TestModel{}), in2, &next); err != nil { t.Fatal(err) }
+    if len(next) != 2 { t.Fatalf("expected 2 rows, got %d", len(next)) }
+
+    // A client-forged PK cursor is rejected with the dedicated code
+    in3 := &pagerpb.Page{Limit: 2, Order: in.Order, Selector: &pagerpb.Page_Cursor{Cursor: encodeTestCursor(t, 1)}}
+    if _, err := pg.ApplyAndScan(ctx, db.NewSelect().Model(&TestModel{}), in3, &next); !isSignatureError(err) {
+        t.Fatalf("expected INVALID_CURSOR_SIGNATURE, got %v", err)
+    }
+}
+
+// encodeTestCursor returns an unsigned PK-only cursor for TestModel's id.
+func encodeTestCursor(t *testing.T, id int64) string {
+    t.Helper()
+    info, err := InferModelInfo(&TestModel{})
+    if err != nil { t.Fatal(err) }
+    token, err := EncodeCursor(nil, map[string]interface{}{"id": id}, info)
+    if err != nil { t.Fatal(err) }
+    return token
+}
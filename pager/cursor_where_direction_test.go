@@ -11,10 +11,10 @@ func TestBuildCursorWhere_MixedDirections_Inequalities(t *testing.T) {
     where, args, err := BuildCursorWhere(cd, plan)
     if err != nil { t.Fatal(err) }
     // Expect: (score > ?) OR (score = ? AND name < ?) OR (score = ? AND name = ? AND id > ?)
-    want := "((score > ?) OR (score = ? AND name < ?) OR (score = ? AND name = ? AND id > ?))"
-    if where != want {
-        t.Fatalf("unexpected WHERE.\nwant: %s\n got: %s", want, where)
+    want := `((("test_model"."score" > 90) OR ("test_model"."score" = 90 AND "test_model"."name" < 'Bob') OR ` +
+        `("test_model"."score" = 90 AND "test_model"."name" = 'Bob' AND "test_model"."id" > 2)))`
+    if got := renderWhere(t, where, args); got != want {
+        t.Fatalf("unexpected WHERE.\nwant: %s\n got: %s", want, got)
     }
-    if len(args) != 6 { t.Fatalf("expected 6 args, got %d", len(args)) }
 }
 
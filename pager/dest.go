@@ -0,0 +1,155 @@
+package pager
+
+import (
+    "database/sql"
+    "fmt"
+    "reflect"
+    "strings"
+    "time"
+
+    "github.com/uptrace/bun"
+)
+
+// destKind is the shape of the rows ApplyAndScan scans into.
+type destKind int
+
+const (
+    destStruct destKind = iota // the model struct or a projection DTO
+    destMap                    // map[string]interface{} keyed by column name
+    destScalar                 // a single selected column
+)
+
+// destShape reads order column values back from scanned destination rows.
+type destShape struct {
+    kind destKind
+    // model is set when rows are the model struct itself.
+    model bool
+    // info addresses columns of struct rows (the model's ModelInfo or the DTO's).
+    info *ModelInfo
+}
+
+// resolveModel returns the model ApplyAndScan pages over and its ModelInfo: the model set
+// on q when there is one, so the destination may be a projection DTO, a map or a scalar
+// slice; otherwise the destination's struct element type.
+func resolveModel(q *bun.SelectQuery, elemType reflect.Type) (interface{}, *ModelInfo, error) {
+    if tm, ok := q.GetModel().(bun.TableModel); ok && tm.Table() != nil {
+        return reflect.New(tm.Table().Type).Interface(), InferModelInfoFromTable(tm.Table()), nil
+    }
+    st := indirectStruct(elemType)
+    if st == nil {
+        return nil, nil, NewInvalidRequestError(fmt.Sprintf("destination of %v requires a query model (q.Model)", elemType))
+    }
+    model := reflect.New(st).Interface()
+    info, err := modelInfoFor(q, model)
+    if err != nil {
+        return nil, nil, err
+    }
+    return model, info, nil
+}
+
+// destShapeFor describes destination rows of elemType for a query over modelType.
+func destShapeFor(q *bun.SelectQuery, elemType, modelType reflect.Type, modelInfo *ModelInfo) (destShape, error) {
+    st := indirectStruct(elemType)
+    switch {
+    case st == modelType:
+        return destShape{kind: destStruct, model: true, info: modelInfo}, nil
+    case st != nil && !isScalarStruct(st):
+        // Projection DTO: columns map onto its fields the way bun scans them
+        info, err := modelInfoFor(q, reflect.New(st).Interface())
+        if err != nil {
+            return destShape{}, err
+        }
+        return destShape{kind: destStruct, info: info}, nil
+    case elemType.Kind() == reflect.Map && elemType.Key().Kind() == reflect.String:
+        return destShape{kind: destMap}, nil
+    }
+    return destShape{kind: destScalar}, nil
+}
+
+var (
+    timeType    = reflect.TypeOf(time.Time{})
+    scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+)
+
+// isScalarStruct reports struct types bun scans as a single value (time.Time, sql.Null*).
+func isScalarStruct(t reflect.Type) bool {
+    return t == timeType || reflect.PointerTo(t).Implements(scannerType)
+}
+
+// missingColumn returns the first cursor column the destination cannot provide ("" if
+// none): struct rows need a field for it, scalar rows must be the plan's only column.
+// Map rows are checked per row by values.
+func (d destShape) missingColumn(orderPlan *OrderPlan, columns []string) string {
+    for _, column := range columns {
+        item := planItem(orderPlan, column)
+        switch d.kind {
+        case destStruct:
+            if item != nil && item.Expr != nil {
+                continue
+            }
+            _, ok := d.info.FieldIndexByColumn[column]
+            if _, rel := d.info.RelationFieldPaths[column]; !ok && !rel {
+                return column
+            }
+        case destScalar:
+            if len(orderPlan.Items) != 1 || orderPlan.Items[0].Column != column {
+                return column
+            }
+        }
+    }
+    return ""
+}
+
+// exprColumn returns the first order expression of the plan when rows are not the model
+// ("" otherwise): OrderExpr.Extract reads the model struct.
+func (d destShape) exprColumn(orderPlan *OrderPlan) string {
+    if d.model {
+        return ""
+    }
+    for _, item := range orderPlan.Items {
+        if item.Expr != nil {
+            return item.Column
+        }
+    }
+    return ""
+}
+
+// values reads the order column values of a scanned row. Map rows hold relation columns
+// under bun's "<alias>__<column>" names; order expressions only occur on model rows
+// (see exprColumn).
+func (d destShape) values(row reflect.Value, orderPlan *OrderPlan) map[string]interface{} {
+    if row.Kind() == reflect.Ptr && d.kind != destScalar {
+        row = row.Elem()
+    }
+    values := make(map[string]interface{})
+    switch d.kind {
+    case destStruct:
+        for _, item := range orderPlan.Items {
+            if val, ok := itemValue(row, item, d.info); ok {
+                values[item.Column] = val
+            }
+        }
+    case destMap:
+        for _, item := range orderPlan.Items {
+            key := reflect.ValueOf(strings.Replace(item.Column, ".", "__", 1)).Convert(row.Type().Key())
+            if val := row.MapIndex(key); val.IsValid() {
+                values[item.Column] = val.Interface()
+            }
+        }
+    case destScalar:
+        if len(orderPlan.Items) == 1 {
+            values[orderPlan.Items[0].Column] = row.Interface()
+        }
+    }
+    return values
+}
+
+// planItem returns the plan item ordering by column, or nil.
+func planItem(orderPlan *OrderPlan, column string) *OrderItem {
+    for i := range orderPlan.Items {
+        if orderPlan.Items[i].Column == column {
+            return &orderPlan.Items[i]
+        }
+    }
+    return nil
+}
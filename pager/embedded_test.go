@@ -0,0 +1,113 @@
+package pager
+
+import (
+    "context"
+    "reflect"
+    "testing"
+
+    pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+    "github.com/uptrace/bun"
+)
+
+type AuditBase struct {
+    ID        int64 `bun:"id,pk,autoincrement"`
+    CreatedAt int64 `bun:"created_at"`
+    UpdatedAt int64 `bun:"updated_at"`
+}
+
+type geoPoint struct {
+    X int `bun:"x"`
+    Y int `bun:"y"`
+}
+
+type embeddedPost struct {
+    bun.BaseModel `bun:"table:posts"`
+    AuditBase
+    Title  string   `bun:"title"`
+    Origin geoPoint `bun:"embed:origin_"`
+}
+
+// embeddedPostView reuses the posts table and shadows updated_at.
+type embeddedPostView struct {
+    embeddedPost `bun:",extend"`
+    UpdatedAt    *int64 `bun:"updated_at"`
+}
+
+type ptrEmbeddedPost struct {
+    bun.BaseModel `bun:"table:posts"`
+    *AuditBase
+    Title string `bun:"title"`
+}
+
+func TestInferModelInfo_Embedded(t *testing.T) {
+    info, err := InferModelInfo(&embeddedPost{})
+    if err != nil { t.Fatal(err) }
+    if len(info.PKColumns) != 1 || info.PKColumns[0] != "id" || info.TableName != "posts" {
+        t.Fatalf("unexpected model info %+v", info)
+    }
+    want := map[string][]int{"id": {1, 0}, "created_at": {1, 1}, "updated_at": {1, 2}, "title": {2}, "origin_x": {3, 0}, "origin_y": {3, 1}}
+    if !reflect.DeepEqual(info.FieldIndexByColumn, want) {
+        t.Fatalf("unexpected field paths %v", info.FieldIndexByColumn)
+    }
+
+    view, err := InferModelInfo(&embeddedPostView{})
+    if err != nil { t.Fatal(err) }
+    if view.TableName != "posts" {
+        t.Fatalf("extend must reuse the embedded table, got %q", view.TableName)
+    }
+    // The shallower field wins
+    if !reflect.DeepEqual(view.FieldIndexByColumn["updated_at"], []int{1}) || !view.NullableColumns["updated_at"] {
+        t.Fatalf("expected top-level updated_at, got %v", view.FieldIndexByColumn["updated_at"])
+    }
+    if !reflect.DeepEqual(view.FieldIndexByColumn["created_at"], []int{0, 1, 1}) {
+        t.Fatalf("unexpected created_at path %v", view.FieldIndexByColumn["created_at"])
+    }
+}
+
+func TestExtractRowValues_Embedded(t *testing.T) {
+    info, err := InferModelInfo(&ptrEmbeddedPost{})
+    if err != nil { t.Fatal(err) }
+    plan := &OrderPlan{Items: []OrderItem{{Column: "created_at", Direction: "DESC"}, {Column: "id", Direction: "DESC"}}}
+    vals, err := ExtractRowValues(&ptrEmbeddedPost{AuditBase: &AuditBase{ID: 7, CreatedAt: 70}}, plan, info)
+    if err != nil { t.Fatal(err) }
+    if vals["id"] != int64(7) || vals["created_at"] != int64(70) {
+        t.Fatalf("unexpected values %v", vals)
+    }
+    // A nil embedded pointer reads as NULL instead of panicking
+    vals, err = ExtractRowValues(&ptrEmbeddedPost{}, plan, info)
+    if err != nil { t.Fatal(err) }
+    if v, ok := vals["id"]; !ok || v != nil {
+        t.Fatalf("expected nil id for a nil embedded pointer, got %v", vals)
+    }
+}
+
+func TestApplyAndScan_EmbeddedModelWalk(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    if _, err := db.NewCreateTable().Model((*embeddedPost)(nil)).Exec(ctx); err != nil {
+        t.Fatal(err)
+    }
+    posts := []embeddedPost{
+        {AuditBase: AuditBase{CreatedAt: 30}, Title: "a"},
+        {AuditBase: AuditBase{CreatedAt: 10}, Title: "b"},
+        {AuditBase: AuditBase{CreatedAt: 30}, Title: "c"},
+        {AuditBase: AuditBase{CreatedAt: 20}, Title: "d"},
+        {AuditBase: AuditBase{CreatedAt: 10}, Title: "e"},
+    }
+    if _, err := db.NewInsert().Model(&posts).Exec(ctx); err != nil {
+        t.Fatal(err)
+    }
+
+    for _, selfContained := range []bool{false, true} {
+        pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error", SelfContainedCursor: selfContained})
+        order := []*pagerpb.Order{{Key: "created_at", Asc: true}}
+        rows := walkPages[embeddedPost](t, pg, func() *bun.SelectQuery { return db.NewSelect().Model((*embeddedPost)(nil)) }, order, 2, false)
+        var got []int64
+        for _, r := range rows { got = append(got, r.ID) }
+        // created_at ASC, PK DESC tiebreaker
+        if !sameIDs(got, []int64{5, 2, 4, 3, 1}) {
+            t.Fatalf("self=%v: unexpected walk %v", selfContained, got)
+        }
+    }
+}
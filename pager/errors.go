@@ -2,6 +2,17 @@ package pager
 
 import "fmt"
 
+// Error codes carried by PagerError.Code.
+const (
+    ErrCodeInvalidRequest         = "INVALID_REQUEST"
+    ErrCodeInternal               = "INTERNAL_ERROR"
+    ErrCodeStaleCursor            = "STALE_CURSOR"
+    ErrCodeInvalidCursorSignature = "INVALID_CURSOR_SIGNATURE"
+    ErrCodeInvalidCursor          = "INVALID_CURSOR"
+    ErrCodeCursorOrderMismatch    = "CURSOR_ORDER_MISMATCH"
+    ErrCodeCursorQueryMismatch    = "CURSOR_QUERY_MISMATCH"
+)
+
 type PagerError struct {
     Code    string
     Message string
@@ -14,21 +25,64 @@ func (e *PagerError) Error() string {
 
 func NewInvalidRequestError(msg string) *PagerError {
 	return &PagerError{
-		Code:    "INVALID_REQUEST",
+		Code:    ErrCodeInvalidRequest,
 		Message: msg,
 	}
 }
 
 func NewInternalError(msg string) *PagerError {
     return &PagerError{
-        Code:    "INTERNAL_ERROR",
+        Code:    ErrCodeInternal,
         Message: msg,
     }
 }
 
 func NewStaleCursorError() *PagerError {
     return &PagerError{
-        Code:    "STALE_CURSOR",
+        Code:    ErrCodeStaleCursor,
         Message: "stale cursor",
     }
 }
+
+// NewInvalidCursorSignatureError reports a cursor that failed authentication: its signature
+// (or AEAD tag) is missing, forged, made with an unknown key, or bound to another model.
+func NewInvalidCursorSignatureError() *PagerError {
+    return &PagerError{
+        Code:    ErrCodeInvalidCursorSignature,
+        Message: "invalid cursor signature",
+    }
+}
+
+// NewInvalidCursorError reports a malformed cursor or one in an unsupported format version.
+func NewInvalidCursorError(msg string) *PagerError {
+    return &PagerError{
+        Code:    ErrCodeInvalidCursor,
+        Message: msg,
+    }
+}
+
+// NewCursorOrderMismatchError reports a cursor produced with a different order than the current request.
+func NewCursorOrderMismatchError() *PagerError {
+    return &PagerError{
+        Code:    ErrCodeCursorOrderMismatch,
+        Message: "cursor was produced with a different order",
+    }
+}
+
+// NewCursorQueryMismatchError reports a cursor produced for a different query (filter) than
+// the current request.
+func NewCursorQueryMismatchError() *PagerError {
+    return &PagerError{
+        Code:    ErrCodeCursorQueryMismatch,
+        Message: "cursor was produced for a different query",
+    }
+}
+
+// newPositionError reports an invalid filter or order_by string at a byte offset.
+func newPositionError(msg string, offset int) *PagerError {
+    return &PagerError{
+        Code:    ErrCodeInvalidRequest,
+        Message: fmt.Sprintf("%s at position %d", msg, offset),
+        Details: map[string]interface{}{"position": offset},
+    }
+}
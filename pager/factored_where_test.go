@@ -0,0 +1,113 @@
+package pager
+
+import (
+    "context"
+    "fmt"
+    "math/rand"
+    "testing"
+
+    pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+    "github.com/uptrace/bun"
+)
+
+type factoredRow struct {
+    ID int64 `bun:"id,pk,autoincrement"`
+    A  int   `bun:"a"`
+    B  int   `bun:"b"`
+    C  int   `bun:"c"`
+}
+
+func TestBuildFactoredCursorWhere(t *testing.T) {
+    cd := &CursorData{Values: map[string]interface{}{"a": 5, "b": 7, "id": int64(3)}}
+    two := &OrderPlan{Items: []OrderItem{{Column: "a", Direction: "DESC"}, {Column: "id", Direction: "DESC"}}}
+    where, args, ok := BuildFactoredCursorWhere(cd, two)
+    want := `("test_model"."a" <= 5 AND (("test_model"."a" < 5) OR ("test_model"."a" = 5 AND "test_model"."id" < 3)))`
+    if !ok || renderWhere(t, where, args) != want {
+        t.Fatalf("unexpected factored where %q %v (ok=%v)", where, args, ok)
+    }
+
+    three := &OrderPlan{Items: []OrderItem{{Column: "a", Direction: "DESC"}, {Column: "b", Direction: "ASC"}, {Column: "id", Direction: "ASC"}}}
+    where, args, ok = BuildFactoredCursorWhere(cd, three)
+    want = `("test_model"."a" <= 5 AND (("test_model"."a" < 5) OR ("test_model"."a" = 5 AND ` +
+        `("test_model"."b" >= 7 AND (("test_model"."b" > 7) OR ("test_model"."b" = 7 AND "test_model"."id" > 3))))))`
+    if !ok || renderWhere(t, where, args) != want {
+        t.Fatalf("unexpected factored where %q %v (ok=%v)", where, args, ok)
+    }
+
+    nullable := &OrderPlan{Items: []OrderItem{{Column: "a", Direction: "DESC", Nulls: NullsFirst}, {Column: "id", Direction: "DESC"}}}
+    if _, _, ok := BuildFactoredCursorWhere(cd, nullable); ok {
+        t.Fatal("NULL placement must fall back to the OR-chain")
+    }
+}
+
+// Randomized check: for random plans and anchors, the factored predicate selects exactly
+// the rows the OR-chain selects.
+func TestBuildFactoredCursorWhere_EquivalentToOrChain(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    if _, err := db.NewCreateTable().Model((*factoredRow)(nil)).Exec(ctx); err != nil {
+        t.Fatal(err)
+    }
+    rnd := rand.New(rand.NewSource(42))
+    rows := make([]factoredRow, 200)
+    for i := range rows {
+        // Small domains force many ties on every prefix
+        rows[i] = factoredRow{A: rnd.Intn(4), B: rnd.Intn(3), C: rnd.Intn(5)}
+    }
+    if _, err := db.NewInsert().Model(&rows).Exec(ctx); err != nil {
+        t.Fatal(err)
+    }
+    if err := db.NewSelect().Model(&rows).Scan(ctx); err != nil {
+        t.Fatal(err)
+    }
+
+    selectIDs := func(where string, args []interface{}) []int64 {
+        var out []int64
+        if err := db.NewSelect().Model((*factoredRow)(nil)).Column("id").Where(where, args...).Order("id").Scan(ctx, &out); err != nil {
+            t.Fatal(err)
+        }
+        return out
+    }
+    dirs := []string{"ASC", "DESC"}
+    for i := 0; i < 100; i++ {
+        cols := rnd.Perm(3)[:1+rnd.Intn(3)]
+        plan := &OrderPlan{}
+        for _, c := range cols {
+            plan.Items = append(plan.Items, OrderItem{Column: []string{"a", "b", "c"}[c], Direction: dirs[rnd.Intn(2)]})
+        }
+        plan.Items = append(plan.Items, OrderItem{Column: "id", Direction: dirs[rnd.Intn(2)]})
+
+        anchor := rows[rnd.Intn(len(rows))]
+        cd := &CursorData{Values: map[string]interface{}{"a": anchor.A, "b": anchor.B, "c": anchor.C, "id": anchor.ID}}
+        chainWhere, chainArgs, err := BuildCursorWhere(cd, plan)
+        if err != nil { t.Fatal(err) }
+        factWhere, factArgs, ok := BuildFactoredCursorWhere(cd, plan)
+        if !ok { t.Fatalf("plan %+v: expected factored predicate", plan.Items) }
+
+        chain, fact := selectIDs(chainWhere, chainArgs), selectIDs(factWhere, factArgs)
+        if !sameIDs(chain, fact) {
+            t.Fatalf("plan %+v anchor %+v: factored %v != OR-chain %v", plan.Items, anchor, fact, chain)
+        }
+    }
+}
+
+func TestApplyAndScan_FactoredStrategyWalk(t *testing.T) {
+    for _, order := range [][]*pagerpb.Order{
+        {{Key: "created_at", Asc: false}},
+        {{Key: "score", Asc: false}, {Key: "name", Asc: true}},
+    } {
+        t.Run(fmt.Sprint(order), func(t *testing.T) {
+            walk := func(strategy CursorWhereStrategy) []int64 {
+                db := setupTestDB(t)
+                defer db.Close()
+                pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error", CursorWhere: strategy})
+                return ids(walkPages[TestModel](t, pg, func() *bun.SelectQuery { return db.NewSelect().Model(&TestModel{}) }, order, 2, false))
+            }
+            chain, factored := walk(CursorWhereOrChain), walk(CursorWhereFactored)
+            if len(chain) != 5 || !sameIDs(chain, factored) {
+                t.Fatalf("factored walk %v differs from OR-chain walk %v", factored, chain)
+            }
+        })
+    }
+}
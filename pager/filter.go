@@ -0,0 +1,325 @@
+package pager
+
+import (
+    "fmt"
+    "math"
+    "reflect"
+    "strconv"
+    "strings"
+    "time"
+
+    pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+    "github.com/uptrace/bun"
+)
+
+// maxFilterDepth bounds the nesting of filter groups.
+const maxFilterDepth = 8
+
+// FilterClause is a Filter translated to SQL: a WHERE fragment, its args, and the
+// relations (bun relation names) it needs joined.
+type FilterClause struct {
+    Where     string
+    Args      []interface{}
+    Relations []string
+}
+
+// BuildFilter translates a Filter into a FilterClause (nil for an empty filter). Fields are
+// model columns or key aliases; allowedKeys, when non-empty, restricts them.
+func BuildFilter(f *pagerpb.Filter, modelInfo *ModelInfo, allowedKeys []string) (*FilterClause, error) {
+    return buildFilter(f, modelInfo, &Options{AllowedFilterKeys: allowedKeys}, nil)
+}
+
+// buildFilter is BuildFilter with the pager's key configuration: AllowedFilterKeys,
+// KeyAliases and StrictKeys. pos, when set, locates errors in the parsed filter string.
+func buildFilter(f *pagerpb.Filter, modelInfo *ModelInfo, opts *Options, pos map[*pagerpb.Filter]*filterPos) (*FilterClause, error) {
+    if f == nil || f.Kind == nil {
+        return nil, nil
+    }
+    b := &filterBuilder{info: modelInfo, opts: opts, allow: map[string]struct{}{}, seen: map[string]bool{}, pos: pos}
+    for _, k := range opts.AllowedFilterKeys {
+        if k = strings.TrimSpace(k); k != "" {
+            b.allow[k] = struct{}{}
+        }
+    }
+    where, err := b.filter(f, 0)
+    if err != nil {
+        return nil, err
+    }
+    return &FilterClause{Where: where, Args: b.args, Relations: b.relations}, nil
+}
+
+// applyFilter adds the request filter to q, joining the relations it references.
+func applyFilter(q *bun.SelectQuery, f *pagerpb.Filter, modelInfo *ModelInfo, opts *Options) (*bun.SelectQuery, error) {
+    clause, err := buildFilter(f, modelInfo, opts, nil)
+    if err != nil {
+        return q, err
+    }
+    return addFilterClause(q, clause), nil
+}
+
+// addFilterClause adds a translated filter to q, joining its relations (no-op for nil).
+func addFilterClause(q *bun.SelectQuery, clause *FilterClause) *bun.SelectQuery {
+    if clause == nil {
+        return q
+    }
+    for _, name := range clause.Relations {
+        q = q.Relation(name)
+    }
+    return q.Where(clause.Where, clause.Args...)
+}
+
+type filterBuilder struct {
+    info      *ModelInfo
+    opts      *Options
+    allow     map[string]struct{}
+    args      []interface{}
+    relations []string
+    seen      map[string]bool
+    // pos holds source offsets of filters parsed from a string (see compileFilter)
+    pos map[*pagerpb.Filter]*filterPos
+}
+
+// filterPos holds the byte offsets of a parsed filter's tokens: the field (or the
+// group's opening token), the operator and each value, and the number of groups
+// nested under (and including) a group.
+type filterPos struct {
+    field, op int
+    values    []int
+    groups    int
+}
+
+// filterPart names the token of a condition an error refers to.
+type filterPart int
+
+const (
+    partField filterPart = iota
+    partOp
+    partValue
+)
+
+// at points an INVALID_REQUEST error at the token of f it concerns when f was parsed
+// from a string; other errors and filters pass through.
+func (b *filterBuilder) at(err error, f *pagerpb.Filter, part filterPart, i int) error {
+    pe, ok := err.(*PagerError)
+    p := b.pos[f]
+    if !ok || p == nil || pe.Code != ErrCodeInvalidRequest {
+        return err
+    }
+    offset := p.field
+    switch {
+    case part == partOp:
+        offset = p.op
+    case part == partValue && i < len(p.values):
+        offset = p.values[i]
+    }
+    return newPositionError(pe.Message, offset)
+}
+
+func (b *filterBuilder) filter(f *pagerpb.Filter, depth int) (string, error) {
+    if depth > maxFilterDepth {
+        return "", b.at(NewInvalidRequestError(fmt.Sprintf("filter nested deeper than %d groups", maxFilterDepth)), f, partField, 0)
+    }
+    switch kind := f.GetKind().(type) {
+    case *pagerpb.Filter_Condition:
+        return b.condition(f, kind.Condition)
+    case *pagerpb.Filter_Group:
+        g := kind.Group
+        if len(g.GetFilters()) == 0 {
+            return "", b.at(NewInvalidRequestError("empty filter group"), f, partField, 0)
+        }
+        sep := " AND "
+        if g.GetLogic() == pagerpb.FilterLogic_FILTER_LOGIC_OR {
+            sep = " OR "
+        }
+        parts := make([]string, 0, len(g.GetFilters()))
+        for _, sub := range g.GetFilters() {
+            part, err := b.filter(sub, depth+1)
+            if err != nil {
+                return "", err
+            }
+            parts = append(parts, part)
+        }
+        if g.GetLogic() == pagerpb.FilterLogic_FILTER_LOGIC_NOT {
+            return "NOT (" + strings.Join(parts, sep) + ")", nil
+        }
+        return "(" + strings.Join(parts, sep) + ")", nil
+    }
+    return "", NewInvalidRequestError("empty filter")
+}
+
+func (b *filterBuilder) condition(f *pagerpb.Filter, c *pagerpb.FilterCondition) (string, error) {
+    key := strings.TrimSpace(c.GetField())
+    if key == "" {
+        return "", b.at(NewInvalidRequestError("filter field is required"), f, partField, 0)
+    }
+    if len(b.allow) > 0 {
+        if _, ok := b.allow[key]; !ok {
+            return "", b.at(NewInvalidRequestError("unsupported filter key: "+key), f, partField, 0)
+        }
+    }
+    column, err := resolveColumnKey(key, "filter", b.info, b.opts)
+    if err != nil {
+        return "", b.at(err, f, partField, 0)
+    }
+    if alias, ok := relationAlias(column); ok {
+        if name, ok := b.info.Relations[alias]; ok && !b.seen[name] {
+            b.seen[name] = true
+            b.relations = append(b.relations, name)
+        }
+    }
+    ref, refArgs := OrderItem{Column: column}.colRef()
+    typ := filterValueType(b.info.ColumnTypes[column])
+
+    op := c.GetOp()
+    switch op {
+    case pagerpb.FilterOp_FILTER_OP_IS_NULL:
+        b.args = append(b.args, refArgs...)
+        switch v := c.GetValue().GetKind().(type) {
+        case nil:
+        case *pagerpb.FilterValue_BoolValue:
+            if !v.BoolValue {
+                return ref + " IS NOT NULL", nil
+            }
+        default:
+            return "", b.at(NewInvalidRequestError(fmt.Sprintf("filter %s: is_null takes a bool value", key)), f, partValue, 0)
+        }
+        return ref + " IS NULL", nil
+    case pagerpb.FilterOp_FILTER_OP_IN, pagerpb.FilterOp_FILTER_OP_NOT_IN:
+        if len(c.GetValues()) == 0 {
+            return "", b.at(NewInvalidRequestError(fmt.Sprintf("filter %s: in/not_in requires values", key)), f, partOp, 0)
+        }
+        values := make([]interface{}, 0, len(c.GetValues()))
+        for i, fv := range c.GetValues() {
+            v, err := filterValue(key, fv, typ)
+            if err != nil {
+                return "", b.at(err, f, partValue, i)
+            }
+            values = append(values, v)
+        }
+        b.args = append(append(b.args, refArgs...), bun.In(values))
+        if op == pagerpb.FilterOp_FILTER_OP_NOT_IN {
+            return ref + " NOT IN (?)", nil
+        }
+        return ref + " IN (?)", nil
+    case pagerpb.FilterOp_FILTER_OP_LIKE:
+        if typ == nil || typ.Kind() != reflect.String {
+            return "", b.at(NewInvalidRequestError(fmt.Sprintf("filter %s: like requires a string field", key)), f, partOp, 0)
+        }
+    }
+
+    sqlOp, ok := filterOps[op]
+    if !ok {
+        return "", b.at(NewInvalidRequestError(fmt.Sprintf("filter %s: unsupported operator %v", key, op)), f, partOp, 0)
+    }
+    v, err := filterValue(key, c.GetValue(), typ)
+    if err != nil {
+        return "", b.at(err, f, partValue, 0)
+    }
+    b.args = append(append(b.args, refArgs...), v)
+    return ref + " " + sqlOp + " ?", nil
+}
+
+var filterOps = map[pagerpb.FilterOp]string{
+    pagerpb.FilterOp_FILTER_OP_UNSPECIFIED: "=",
+    pagerpb.FilterOp_FILTER_OP_EQ:          "=",
+    pagerpb.FilterOp_FILTER_OP_NE:          "<>",
+    pagerpb.FilterOp_FILTER_OP_LT:          "<",
+    pagerpb.FilterOp_FILTER_OP_LTE:         "<=",
+    pagerpb.FilterOp_FILTER_OP_GT:          ">",
+    pagerpb.FilterOp_FILTER_OP_GTE:         ">=",
+    pagerpb.FilterOp_FILTER_OP_LIKE:        "LIKE",
+}
+
+// filterValueType is the Go type a column's filter values convert to: pointers are
+// dereferenced and sql.Null* wrappers resolve to their value field.
+func filterValueType(t reflect.Type) reflect.Type {
+    for t != nil && t.Kind() == reflect.Ptr {
+        t = t.Elem()
+    }
+    if t != nil && t != timeType && isNullableType(t) && t.NumField() > 0 {
+        return t.Field(0).Type
+    }
+    return t
+}
+
+// filterValue converts a FilterValue to the field's Go type; a value of another type
+// is INVALID_REQUEST. Fields of unknown type take the value as is.
+func filterValue(key string, fv *pagerpb.FilterValue, t reflect.Type) (interface{}, error) {
+    var v interface{}
+    switch kind := fv.GetKind().(type) {
+    case *pagerpb.FilterValue_StringValue:
+        v = kind.StringValue
+    case *pagerpb.FilterValue_IntValue:
+        v = kind.IntValue
+    case *pagerpb.FilterValue_UintValue:
+        v = kind.UintValue
+    case *pagerpb.FilterValue_DoubleValue:
+        v = kind.DoubleValue
+    case *pagerpb.FilterValue_BoolValue:
+        v = kind.BoolValue
+    default:
+        return nil, NewInvalidRequestError(fmt.Sprintf("filter %s: value is required", key))
+    }
+    if t == nil {
+        return v, nil
+    }
+    mismatch := NewInvalidRequestError(fmt.Sprintf("filter %s: %v is not a valid %v", key, v, t))
+    if t == timeType {
+        s, ok := v.(string)
+        if !ok {
+            return nil, mismatch
+        }
+        if tv, err := time.Parse(time.RFC3339Nano, s); err == nil {
+            return tv, nil
+        }
+        if tv, err := time.Parse(time.DateOnly, s); err == nil {
+            return tv, nil
+        }
+        return nil, mismatch
+    }
+    switch t.Kind() {
+    case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+        if f, ok := v.(float64); ok && f != math.Trunc(f) {
+            return nil, mismatch
+        }
+        if iv, ok := coerceToKind(v, reflect.Int64).(int64); ok {
+            return iv, nil
+        }
+    case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+        if f, ok := v.(float64); ok && f != math.Trunc(f) {
+            return nil, mismatch
+        }
+        if uv, ok := coerceToKind(v, reflect.Uint64).(uint64); ok {
+            return uv, nil
+        }
+    case reflect.Float32, reflect.Float64:
+        switch nv := v.(type) {
+        case float64:
+            return nv, nil
+        case int64:
+            return float64(nv), nil
+        case uint64:
+            return float64(nv), nil
+        case string:
+            if f, err := strconv.ParseFloat(nv, 64); err == nil {
+                return f, nil
+            }
+        }
+    case reflect.Bool:
+        switch nv := v.(type) {
+        case bool:
+            return nv, nil
+        case string:
+            if bv, err := strconv.ParseBool(nv); err == nil {
+                return bv, nil
+            }
+        }
+    case reflect.String:
+        if _, ok := v.(bool); !ok {
+            return coerceToKind(v, reflect.String), nil
+        }
+    default:
+        return v, nil
+    }
+    return nil, mismatch
+}
@@ -0,0 +1,417 @@
+package pager
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+    "unicode"
+
+    pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+    "github.com/uptrace/bun"
+)
+
+// ParseFilter parses an AIP-160 filter string ("status = 1 AND created_at > \"2025-01-01\"")
+// into a Filter; a blank string yields nil. Supported: comparisons (= != < <= > >= and :
+// as equality), AND, OR (binding tighter than AND, as in AIP-160), whitespace as AND,
+// NOT and - negation, parentheses, quoted strings, numbers, true/false and null
+// (= null: IS NULL, != null: IS NOT NULL). Syntax errors are INVALID_REQUEST naming the
+// byte offset of the offending token (also in Details["position"]).
+func ParseFilter(expr string) (*pagerpb.Filter, error) {
+    f, _, err := parseFilter(expr)
+    return f, err
+}
+
+// CompileFilter parses an AIP-160 filter string and translates it like BuildFilter;
+// unknown fields and mismatched values are reported at their position in expr.
+func CompileFilter(expr string, modelInfo *ModelInfo, allowedKeys []string) (*FilterClause, error) {
+    return compileFilter(expr, modelInfo, &Options{AllowedFilterKeys: allowedKeys})
+}
+
+func compileFilter(expr string, modelInfo *ModelInfo, opts *Options) (*FilterClause, error) {
+    f, pos, err := parseFilter(expr)
+    if err != nil {
+        return nil, err
+    }
+    return buildFilter(f, modelInfo, opts, pos)
+}
+
+// ApplyFilterString adds an AIP-160 filter string to q under the pager's AllowedFilterKeys,
+// KeyAliases and StrictKeys, joining the relations it references. q must have a model.
+func (p *Pager) ApplyFilterString(q *bun.SelectQuery, expr string) (*bun.SelectQuery, error) {
+    q, _, err := p.applyFilterString(q, expr)
+    return q, err
+}
+
+// applyFilterString is ApplyFilterString also returning the parsed filter (nil for a blank expr).
+func (p *Pager) applyFilterString(q *bun.SelectQuery, expr string) (*bun.SelectQuery, *pagerpb.Filter, error) {
+    tm, ok := q.GetModel().(bun.TableModel)
+    if !ok || tm.Table() == nil {
+        return nil, nil, NewInvalidRequestError("filter requires a query model (q.Model)")
+    }
+    f, pos, err := parseFilter(expr)
+    if err != nil {
+        return nil, nil, err
+    }
+    clause, err := buildFilter(f, InferModelInfoFromTable(tm.Table()), p.opts, pos)
+    if err != nil {
+        if pe, ok := err.(*PagerError); ok {
+            return nil, nil, pe
+        }
+        return nil, nil, NewInternalError(fmt.Sprintf("failed to build filter: %v", err))
+    }
+    return addFilterClause(q, clause), f, nil
+}
+
+type filterTokenKind int
+
+const (
+    tokEOF filterTokenKind = iota
+    tokText                // field names, keywords and bare values
+    tokString              // quoted string
+    tokComparator
+    tokLParen
+    tokRParen
+)
+
+type filterToken struct {
+    kind filterTokenKind
+    text string // unquoted for strings
+    pos  int
+}
+
+func (t filterToken) String() string {
+    if t.kind == tokEOF {
+        return "end of filter"
+    }
+    return strconv.Quote(t.text)
+}
+
+func lexFilter(expr string) ([]filterToken, error) {
+    var toks []filterToken
+    for i := 0; i < len(expr); {
+        c := expr[i]
+        switch {
+        case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+            i++
+        case c == '(':
+            toks = append(toks, filterToken{tokLParen, "(", i})
+            i++
+        case c == ')':
+            toks = append(toks, filterToken{tokRParen, ")", i})
+            i++
+        case c == '"' || c == '\'':
+            s, n, err := lexString(expr[i:], i)
+            if err != nil {
+                return nil, err
+            }
+            toks = append(toks, filterToken{tokString, s, i})
+            i += n
+        case strings.IndexByte("=!<>:", c) >= 0:
+            op := string(c)
+            if i+1 < len(expr) && expr[i+1] == '=' && c != '=' && c != ':' {
+                op += "="
+            }
+            if op == "!" {
+                return nil, newPositionError(`filter: expected "!="`, i)
+            }
+            toks = append(toks, filterToken{tokComparator, op, i})
+            i += len(op)
+        default:
+            start := i
+            for i < len(expr) && !isFilterDelim(expr[i]) {
+                i++
+            }
+            toks = append(toks, filterToken{tokText, expr[start:i], start})
+        }
+    }
+    return append(toks, filterToken{kind: tokEOF, pos: len(expr)}), nil
+}
+
+func isFilterDelim(c byte) bool {
+    return strings.IndexByte(" \t\n\r()\"'=!<>:", c) >= 0
+}
+
+// lexString reads a quoted string at the start of s (offset is its position in the
+// filter) and returns its value and length.
+func lexString(s string, offset int) (string, int, error) {
+    quote := s[0]
+    var sb strings.Builder
+    for i := 1; i < len(s); i++ {
+        switch c := s[i]; c {
+        case quote:
+            return sb.String(), i + 1, nil
+        case '\\':
+            if i+1 == len(s) {
+                break
+            }
+            i++
+            switch e := s[i]; e {
+            case 'n':
+                sb.WriteByte('\n')
+            case 't':
+                sb.WriteByte('\t')
+            case '\\', '"', '\'':
+                sb.WriteByte(e)
+            default:
+                return "", 0, newPositionError(fmt.Sprintf(`filter: unknown escape "\%c"`, e), offset+i-1)
+            }
+        default:
+            sb.WriteByte(c)
+        }
+    }
+    return "", 0, newPositionError("filter: unterminated string", offset)
+}
+
+// filterParser is a recursive-descent parser over the AIP-160 grammar:
+//
+//  expression: sequence {AND sequence}
+//  sequence:   factor {factor}
+//  factor:     term {OR term}
+//  term:       [NOT | -] simple
+//  simple:     field comparator value | ( expression )
+type filterParser struct {
+    toks   []filterToken
+    i      int
+    parens int
+    pos    map[*pagerpb.Filter]*filterPos
+}
+
+// maxFilterParens bounds parenthesis nesting, which need not create groups (e.g.
+// "((a = 1))"), to keep the recursion shallow; group nesting is bounded by maxFilterDepth.
+const maxFilterParens = 64
+
+func parseFilter(expr string) (*pagerpb.Filter, map[*pagerpb.Filter]*filterPos, error) {
+    if strings.TrimSpace(expr) == "" {
+        return nil, nil, nil
+    }
+    toks, err := lexFilter(expr)
+    if err != nil {
+        return nil, nil, err
+    }
+    p := &filterParser{toks: toks, pos: map[*pagerpb.Filter]*filterPos{}}
+    f, err := p.expression()
+    if err != nil {
+        return nil, nil, err
+    }
+    if t := p.peek(); t.kind != tokEOF {
+        return nil, nil, p.unexpected(t)
+    }
+    return f, p.pos, nil
+}
+
+func (p *filterParser) peek() filterToken { return p.toks[p.i] }
+
+func (p *filterParser) next() filterToken {
+    t := p.toks[p.i]
+    if t.kind != tokEOF {
+        p.i++
+    }
+    return t
+}
+
+func (p *filterParser) keyword(t filterToken, word string) bool {
+    return t.kind == tokText && t.text == word
+}
+
+func (p *filterParser) unexpected(t filterToken) error {
+    return newPositionError(fmt.Sprintf("filter: unexpected %v", t), t.pos)
+}
+
+// group combines filters under logic, keeping a single filter as is. Groups are
+// counted like the translator does, so accepted strings never nest too deep to build.
+func (p *filterParser) group(logic pagerpb.FilterLogic, filters []*pagerpb.Filter, at int) (*pagerpb.Filter, error) {
+    if len(filters) == 1 && logic != pagerpb.FilterLogic_FILTER_LOGIC_NOT {
+        return filters[0], nil
+    }
+    groups := 1
+    for _, sub := range filters {
+        if sp := p.pos[sub]; sp != nil && sp.groups >= groups {
+            groups = sp.groups + 1
+        }
+    }
+    if groups > maxFilterDepth {
+        return nil, newPositionError(fmt.Sprintf("filter: nested deeper than %d groups", maxFilterDepth), at)
+    }
+    f := &pagerpb.Filter{Kind: &pagerpb.Filter_Group{Group: &pagerpb.FilterGroup{Logic: logic, Filters: filters}}}
+    p.pos[f] = &filterPos{field: at, op: at, groups: groups}
+    return f, nil
+}
+
+func (p *filterParser) expression() (*pagerpb.Filter, error) {
+    at := p.peek().pos
+    seq, err := p.sequence()
+    if err != nil {
+        return nil, err
+    }
+    filters := []*pagerpb.Filter{seq}
+    for p.keyword(p.peek(), "AND") {
+        p.next()
+        if seq, err = p.sequence(); err != nil {
+            return nil, err
+        }
+        filters = append(filters, seq)
+    }
+    return p.group(pagerpb.FilterLogic_FILTER_LOGIC_AND, filters, at)
+}
+
+func (p *filterParser) sequence() (*pagerpb.Filter, error) {
+    at := p.peek().pos
+    var filters []*pagerpb.Filter
+    for {
+        f, err := p.factor()
+        if err != nil {
+            return nil, err
+        }
+        filters = append(filters, f)
+        t := p.peek()
+        if t.kind == tokEOF || t.kind == tokRParen || p.keyword(t, "AND") {
+            return p.group(pagerpb.FilterLogic_FILTER_LOGIC_AND, filters, at)
+        }
+    }
+}
+
+func (p *filterParser) factor() (*pagerpb.Filter, error) {
+    at := p.peek().pos
+    term, err := p.term()
+    if err != nil {
+        return nil, err
+    }
+    filters := []*pagerpb.Filter{term}
+    for p.keyword(p.peek(), "OR") {
+        p.next()
+        if term, err = p.term(); err != nil {
+            return nil, err
+        }
+        filters = append(filters, term)
+    }
+    return p.group(pagerpb.FilterLogic_FILTER_LOGIC_OR, filters, at)
+}
+
+func (p *filterParser) term() (*pagerpb.Filter, error) {
+    t := p.peek()
+    negate := p.keyword(t, "NOT") || p.keyword(t, "-")
+    if negate {
+        p.next()
+    } else if t.kind == tokText && len(t.text) > 1 && t.text[0] == '-' {
+        // "-field" negates the restriction that follows
+        negate = true
+        p.toks[p.i] = filterToken{tokText, t.text[1:], t.pos + 1}
+    }
+    f, err := p.simple()
+    if err != nil || !negate {
+        return f, err
+    }
+    return p.group(pagerpb.FilterLogic_FILTER_LOGIC_NOT, []*pagerpb.Filter{f}, t.pos)
+}
+
+func (p *filterParser) simple() (*pagerpb.Filter, error) {
+    t := p.next()
+    if t.kind == tokLParen {
+        if p.parens++; p.parens > maxFilterParens {
+            return nil, newPositionError(fmt.Sprintf("filter: nested deeper than %d parentheses", maxFilterParens), t.pos)
+        }
+        f, err := p.expression()
+        if err != nil {
+            return nil, err
+        }
+        if r := p.next(); r.kind != tokRParen {
+            return nil, newPositionError(fmt.Sprintf("filter: expected \")\" to close the group at position %d, got %v", t.pos, r), r.pos)
+        }
+        p.parens--
+        return f, nil
+    }
+    return p.restriction(t)
+}
+
+func (p *filterParser) restriction(field filterToken) (*pagerpb.Filter, error) {
+    if field.kind != tokText || !isFieldPath(field.text) || p.keyword(field, "AND") || p.keyword(field, "OR") || p.keyword(field, "NOT") {
+        return nil, newPositionError(fmt.Sprintf("filter: expected a field name, got %v", field), field.pos)
+    }
+    op := p.next()
+    switch op.kind {
+    case tokComparator:
+    case tokLParen:
+        return nil, newPositionError(fmt.Sprintf("filter: functions are not supported (%s)", field.text), field.pos)
+    default:
+        return nil, newPositionError(fmt.Sprintf("filter: expected a comparator after %q, got %v", field.text, op), op.pos)
+    }
+    arg := p.next()
+    if arg.kind != tokText && arg.kind != tokString {
+        return nil, newPositionError(fmt.Sprintf("filter: expected a value after %q, got %v", op.text, arg), arg.pos)
+    }
+
+    c := &pagerpb.FilterCondition{Field: field.text, Op: filterComparators[op.text]}
+    if arg.kind == tokText && arg.text == "null" {
+        switch c.Op {
+        case pagerpb.FilterOp_FILTER_OP_EQ:
+            c.Op = pagerpb.FilterOp_FILTER_OP_IS_NULL
+        case pagerpb.FilterOp_FILTER_OP_NE:
+            c.Op, c.Value = pagerpb.FilterOp_FILTER_OP_IS_NULL, &pagerpb.FilterValue{Kind: &pagerpb.FilterValue_BoolValue{BoolValue: false}}
+        default:
+            return nil, newPositionError(fmt.Sprintf("filter: null only compares with = or != (got %q)", op.text), op.pos)
+        }
+    } else {
+        c.Value = filterLiteral(arg)
+    }
+    f := &pagerpb.Filter{Kind: &pagerpb.Filter_Condition{Condition: c}}
+    p.pos[f] = &filterPos{field: field.pos, op: op.pos, values: []int{arg.pos}}
+    return f, nil
+}
+
+var filterComparators = map[string]pagerpb.FilterOp{
+    "=":  pagerpb.FilterOp_FILTER_OP_EQ,
+    ":":  pagerpb.FilterOp_FILTER_OP_EQ,
+    "!=": pagerpb.FilterOp_FILTER_OP_NE,
+    "<":  pagerpb.FilterOp_FILTER_OP_LT,
+    "<=": pagerpb.FilterOp_FILTER_OP_LTE,
+    ">":  pagerpb.FilterOp_FILTER_OP_GT,
+    ">=": pagerpb.FilterOp_FILTER_OP_GTE,
+}
+
+// isFieldPath reports whether s is a (dotted) field name.
+func isFieldPath(s string) bool {
+    for i, r := range s {
+        if !(r == '_' || unicode.IsLetter(r) || (i > 0 && (unicode.IsDigit(r) || r == '.'))) {
+            return false
+        }
+    }
+    return s != "" && !strings.HasSuffix(s, ".")
+}
+
+// filterLiteral types a value token: quoted strings stay strings; bare true/false,
+// integers and decimals are typed, other bare text is a string. The translator converts
+// the value to the field's type.
+func filterLiteral(t filterToken) *pagerpb.FilterValue {
+    if t.kind == tokText {
+        switch {
+        case t.text == "true" || t.text == "false":
+            return &pagerpb.FilterValue{Kind: &pagerpb.FilterValue_BoolValue{BoolValue: t.text == "true"}}
+        case isFilterNumber(t.text):
+            if iv, err := strconv.ParseInt(t.text, 10, 64); err == nil {
+                return &pagerpb.FilterValue{Kind: &pagerpb.FilterValue_IntValue{IntValue: iv}}
+            }
+            if uv, err := strconv.ParseUint(t.text, 10, 64); err == nil {
+                return &pagerpb.FilterValue{Kind: &pagerpb.FilterValue_UintValue{UintValue: uv}}
+            }
+            if fv, err := strconv.ParseFloat(t.text, 64); err == nil {
+                return &pagerpb.FilterValue{Kind: &pagerpb.FilterValue_DoubleValue{DoubleValue: fv}}
+            }
+        }
+    }
+    return &pagerpb.FilterValue{Kind: &pagerpb.FilterValue_StringValue{StringValue: t.text}}
+}
+
+// isFilterNumber reports whether s is a decimal number literal (optional sign, digits,
+// at most one point, optional exponent); "inf", "NaN" and hex stay text.
+func isFilterNumber(s string) bool {
+    s = strings.TrimPrefix(s, "-")
+    if s == "" || !(s[0] >= '0' && s[0] <= '9') {
+        return false
+    }
+    for _, r := range s {
+        if !(r >= '0' && r <= '9' || r == '.' || r == 'e' || r == 'E' || r == '+' || r == '-') {
+            return false
+        }
+    }
+    return true
+}
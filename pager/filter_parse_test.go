@@ -0,0 +1,220 @@
+package pager
+
+import (
+    "context"
+    "strings"
+    "testing"
+    "time"
+
+    pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+    "github.com/uptrace/bun"
+)
+
+type filterEvent struct {
+    ID int64     `bun:"id,pk,autoincrement"`
+    At time.Time `bun:"at"`
+}
+
+// expectFilterPosition checks an INVALID_REQUEST error located at offset.
+func expectFilterPosition(t *testing.T, err error, offset int) {
+    t.Helper()
+    pe, ok := err.(*PagerError)
+    if !ok || pe.Code != ErrCodeInvalidRequest {
+        t.Fatalf("expected INVALID_REQUEST, got %v", err)
+    }
+    if pe.Details["position"] != offset {
+        t.Fatalf("expected position %d, got %v (%s)", offset, pe.Details["position"], pe.Message)
+    }
+}
+
+func TestParseFilter_Grammar(t *testing.T) {
+    info := mustModelInfo(t, &TestModel{})
+    // OR binds tighter than AND; whitespace is AND
+    clause, err := CompileFilter(`name = "a" score = 1 OR score:2 AND NOT created_at > 5`, info, nil)
+    if err != nil { t.Fatal(err) }
+    want := "((?TableAlias.? = ? AND (?TableAlias.? = ? OR ?TableAlias.? = ?)) AND NOT (?TableAlias.? > ?))"
+    if clause.Where != want {
+        t.Fatalf("expected %s, got %s", want, clause.Where)
+    }
+    if args := clause.Args; args[1] != "a" || args[3] != int64(1) || args[6] != bun.Ident("created_at") {
+        t.Fatalf("unexpected args %v", args)
+    }
+
+    f, err := ParseFilter(`-(name = 'it\'s') id != null`)
+    if err != nil { t.Fatal(err) }
+    g := f.GetGroup()
+    if len(g.GetFilters()) != 2 || g.Filters[0].GetGroup().GetLogic() != pagerpb.FilterLogic_FILTER_LOGIC_NOT {
+        t.Fatalf("unexpected tree %v", f)
+    }
+    if c := g.Filters[0].GetGroup().Filters[0].GetCondition(); c.GetValue().GetStringValue() != "it's" {
+        t.Fatalf("unexpected escaped string %v", c)
+    }
+    if c := g.Filters[1].GetCondition(); c.GetOp() != pagerpb.FilterOp_FILTER_OP_IS_NULL || c.GetValue().GetBoolValue() {
+        t.Fatalf("expected IS NOT NULL, got %v", c)
+    }
+
+    for expr, kind := range map[string]string{"-5": "int", "18446744073709551615": "uint", "1.5e3": "double", "true": "bool", "2025-01-01": "string", "ACTIVE": "string"} {
+        f, err := ParseFilter("x = " + expr)
+        if err != nil { t.Fatal(err) }
+        var got string
+        switch f.GetCondition().GetValue().GetKind().(type) {
+        case *pagerpb.FilterValue_IntValue:
+            got = "int"
+        case *pagerpb.FilterValue_UintValue:
+            got = "uint"
+        case *pagerpb.FilterValue_DoubleValue:
+            got = "double"
+        case *pagerpb.FilterValue_BoolValue:
+            got = "bool"
+        case *pagerpb.FilterValue_StringValue:
+            got = "string"
+        }
+        if got != kind {
+            t.Fatalf("%s: expected %s literal, got %s", expr, kind, got)
+        }
+    }
+
+    if f, err := ParseFilter("  "); f != nil || err != nil {
+        t.Fatalf("blank filter must parse to nil, got %v %v", f, err)
+    }
+}
+
+func TestParseFilter_ErrorPositions(t *testing.T) {
+    for _, tc := range []struct {
+        expr string
+        pos  int
+    }{
+        {`score = `, 8},
+        {`score 5`, 6},
+        {`= 5`, 0},
+        {`(score = 1`, 10},
+        {`score = 1)`, 9},
+        {`name = "abc`, 7},
+        {`name = "a\q"`, 9},
+        {`score ! 1`, 6},
+        {`lower(name) = "a"`, 0},
+        {`score < null`, 6},
+        {`score = 1 AND OR name = "a"`, 14},
+        {`NOT NOT score = 1`, 4},
+        {strings.Repeat("(", maxFilterParens+1) + "score = 1" + strings.Repeat(")", maxFilterParens+1), maxFilterParens},
+        {"NOT (" + nestedFilter(maxFilterDepth/2) + ")", 0},
+    } {
+        _, err := ParseFilter(tc.expr)
+        expectFilterPosition(t, err, tc.pos)
+    }
+
+    // Fields and values are checked against the model at their position
+    info := mustModelInfo(t, &TestModel{})
+    for _, tc := range []struct {
+        expr string
+        opts Options
+        pos  int
+    }{
+        {`name = "a" AND nope = 1`, Options{}, 15},
+        {`score > "high"`, Options{}, 8},
+        {`score = 1.5`, Options{}, 8},
+        {`name = true`, Options{}, 7},
+        {`score = 1 OR name = "a"`, Options{AllowedFilterKeys: []string{"score"}}, 13},
+        {`created_at > 1`, Options{StrictKeys: true}, 0},
+    } {
+        _, err := compileFilter(tc.expr, info, &tc.opts)
+        expectFilterPosition(t, err, tc.pos)
+    }
+}
+
+func TestApplyFilterString(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    pg := New(&Options{DefaultLimit: 10, MaxLimit: 10, LogLevel: "error"})
+    in := &pagerpb.Page{Order: []*pagerpb.Order{{Key: "id", Asc: true}}, IncludeTotal: true}
+
+    for expr, want := range map[string][]int64{
+        `score >= 88 AND NOT name = "Eve"`: {1, 3},
+        `created_at > 1000 -name:Bob`:      {3, 4, 5},
+        `score < 85 OR score > 90`:         {3, 4},
+        `name = null`:                      nil,
+        `name != null`:                     {1, 2, 3, 4, 5},
+    } {
+        q, err := pg.ApplyFilterString(db.NewSelect().Model((*TestModel)(nil)), expr)
+        if err != nil { t.Fatal(err) }
+        var rows []TestModel
+        out, err := pg.ApplyAndScan(ctx, q, in, &rows)
+        if err != nil { t.Fatal(err) }
+        if !sameIDs(ids(rows), want) || out.PageInfo.TotalCount != uint64(len(want)) {
+            t.Fatalf("%s: expected %v, got %v (total %d)", expr, want, ids(rows), out.PageInfo.TotalCount)
+        }
+    }
+
+    _, err := pg.ApplyFilterString(db.NewSelect().Model((*TestModel)(nil)), `score = "x"`)
+    expectFilterPosition(t, err, 8)
+    _, err = pg.ApplyFilterString(db.NewSelect().Table("test_models"), `score = 1`)
+    expectInvalidRequest(t, err)
+
+    // Relation columns join on demand
+    bdb := setupBooksDB(t)
+    defer bdb.Close()
+    q, err := pg.ApplyFilterString(bdb.NewSelect().Model((*joinBook)(nil)), `author.name = "Ann"`)
+    if err != nil { t.Fatal(err) }
+    var books []joinBook
+    if _, err := pg.ApplyAndScan(ctx, q, in, &books); err != nil { t.Fatal(err) }
+    if len(books) != 2 || books[0].ID != 2 || books[1].ID != 4 {
+        t.Fatalf("unexpected books %+v", books)
+    }
+}
+
+// nestedFilter nests "score > 85" under 2*levels groups: each level adds a NOT and an
+// OR group, so an even number of levels keeps the filter's meaning.
+func nestedFilter(levels int) string {
+    expr := "score > 85"
+    for i := 0; i < levels; i++ {
+        expr = "NOT (score < 0 OR " + expr + ")"
+    }
+    return expr
+}
+
+func TestApplyFilterString_MaxDepth(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    pg := New(&Options{DefaultLimit: 10, MaxLimit: 10, LogLevel: "error"})
+
+    // A string at the parser's maximum group depth must translate
+    expr := nestedFilter(maxFilterDepth / 2)
+    q, err := pg.ApplyFilterString(db.NewSelect().Model((*TestModel)(nil)), expr)
+    if err != nil { t.Fatalf("%s: %v", expr, err) }
+    var rows []TestModel
+    if _, err := pg.ApplyAndScan(context.Background(), q, &pagerpb.Page{Order: []*pagerpb.Order{{Key: "id", Asc: true}}}, &rows); err != nil { t.Fatal(err) }
+    if !sameIDs(ids(rows), []int64{1, 3, 5}) {
+        t.Fatalf("unexpected rows %v", ids(rows))
+    }
+    _, err = pg.ApplyFilterString(db.NewSelect().Model((*TestModel)(nil)), "NOT ("+expr+")")
+    expectFilterPosition(t, err, 0)
+}
+
+func TestApplyFilterString_Timestamps(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    if _, err := db.NewCreateTable().Model((*filterEvent)(nil)).Exec(ctx); err != nil { t.Fatal(err) }
+    day := func(s string) time.Time { d, _ := time.Parse(time.DateOnly, s); return d }
+    events := []filterEvent{{At: day("2024-12-31")}, {At: day("2025-01-02")}, {At: day("2025-03-01")}}
+    if _, err := db.NewInsert().Model(&events).Exec(ctx); err != nil { t.Fatal(err) }
+    pg := New(&Options{DefaultLimit: 10, MaxLimit: 10, LogLevel: "error"})
+
+    for expr, want := range map[string][]int64{
+        `at > "2025-01-01"`:            {2, 3},
+        `at >= "2025-03-01T00:00:00Z"`: {3},
+    } {
+        q, err := pg.ApplyFilterString(db.NewSelect().Model((*filterEvent)(nil)), expr)
+        if err != nil { t.Fatal(err) }
+        var rows []filterEvent
+        if _, err := pg.ApplyAndScan(ctx, q, &pagerpb.Page{Order: []*pagerpb.Order{{Key: "id", Asc: true}}}, &rows); err != nil { t.Fatal(err) }
+        got := make([]int64, len(rows))
+        for i, r := range rows { got[i] = r.ID }
+        if !sameIDs(got, want) {
+            t.Fatalf("%s: expected %v, got %v", expr, want, got)
+        }
+    }
+    _, err := pg.ApplyFilterString(db.NewSelect().Model((*filterEvent)(nil)), `at > "yesterday"`)
+    expectFilterPosition(t, err, 5)
+}
@@ -0,0 +1,200 @@
+package pager
+
+import (
+    "context"
+    "testing"
+
+    pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+    "github.com/uptrace/bun"
+)
+
+func cond(field string, op pagerpb.FilterOp, values ...*pagerpb.FilterValue) *pagerpb.Filter {
+    c := &pagerpb.FilterCondition{Field: field, Op: op}
+    if op == pagerpb.FilterOp_FILTER_OP_IN || op == pagerpb.FilterOp_FILTER_OP_NOT_IN {
+        c.Values = values
+    } else if len(values) > 0 {
+        c.Value = values[0]
+    }
+    return &pagerpb.Filter{Kind: &pagerpb.Filter_Condition{Condition: c}}
+}
+
+func group(logic pagerpb.FilterLogic, filters ...*pagerpb.Filter) *pagerpb.Filter {
+    return &pagerpb.Filter{Kind: &pagerpb.Filter_Group{Group: &pagerpb.FilterGroup{Logic: logic, Filters: filters}}}
+}
+
+func strVal(s string) *pagerpb.FilterValue {
+    return &pagerpb.FilterValue{Kind: &pagerpb.FilterValue_StringValue{StringValue: s}}
+}
+
+func intVal(i int64) *pagerpb.FilterValue {
+    return &pagerpb.FilterValue{Kind: &pagerpb.FilterValue_IntValue{IntValue: i}}
+}
+
+func boolVal(b bool) *pagerpb.FilterValue {
+    return &pagerpb.FilterValue{Kind: &pagerpb.FilterValue_BoolValue{BoolValue: b}}
+}
+
+func TestBuildFilter_SQL(t *testing.T) {
+    info := mustModelInfo(t, &aliasedModel{})
+    f := group(pagerpb.FilterLogic_FILTER_LOGIC_OR,
+        cond("createdAt", pagerpb.FilterOp_FILTER_OP_GTE, strVal("2000")),
+        cond("name", pagerpb.FilterOp_FILTER_OP_IN, strVal("a"), strVal("b")),
+    )
+    clause, err := BuildFilter(f, info, nil)
+    if err != nil { t.Fatal(err) }
+    if clause.Where != "(?TableAlias.? >= ? OR ?TableAlias.? IN (?))" || len(clause.Args) != 4 {
+        t.Fatalf("unexpected clause %q %v", clause.Where, clause.Args)
+    }
+    if clause.Args[0] != bun.Ident("created_at") || clause.Args[1] != int64(2000) {
+        t.Fatalf("expected aliased column and coerced value, got %v", clause.Args)
+    }
+    if clause, err := BuildFilter(nil, info, nil); clause != nil || err != nil {
+        t.Fatalf("nil filter must yield no clause, got %v %v", clause, err)
+    }
+}
+
+func TestApplyAndScan_FilterOperators(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    pg := New(&Options{DefaultLimit: 10, MaxLimit: 10, LogLevel: "error"})
+
+    for _, tc := range []struct {
+        name   string
+        filter *pagerpb.Filter
+        want   []int64
+    }{
+        {"eq", cond("name", pagerpb.FilterOp_FILTER_OP_EQ, strVal("Bob")), []int64{2}},
+        {"eq default op", cond("score", pagerpb.FilterOp_FILTER_OP_UNSPECIFIED, intVal(95)), []int64{3}},
+        {"ne", cond("name", pagerpb.FilterOp_FILTER_OP_NE, strVal("Bob")), []int64{1, 3, 4, 5}},
+        {"lt", cond("score", pagerpb.FilterOp_FILTER_OP_LT, intVal(88)), []int64{2, 4}},
+        {"lte", cond("score", pagerpb.FilterOp_FILTER_OP_LTE, intVal(88)), []int64{2, 4, 5}},
+        {"gt", cond("created_at", pagerpb.FilterOp_FILTER_OP_GT, intVal(3000)), []int64{4, 5}},
+        {"gte string value", cond("score", pagerpb.FilterOp_FILTER_OP_GTE, strVal("90")), []int64{1, 3}},
+        {"in", cond("name", pagerpb.FilterOp_FILTER_OP_IN, strVal("Bob"), strVal("Eve")), []int64{2, 5}},
+        {"not in", cond("id", pagerpb.FilterOp_FILTER_OP_NOT_IN, intVal(1), intVal(2)), []int64{3, 4, 5}},
+        {"like", cond("name", pagerpb.FilterOp_FILTER_OP_LIKE, strVal("%e")), []int64{1, 3, 5}},
+        {"or", group(pagerpb.FilterLogic_FILTER_LOGIC_OR,
+            cond("score", pagerpb.FilterOp_FILTER_OP_LT, intVal(85)),
+            cond("name", pagerpb.FilterOp_FILTER_OP_EQ, strVal("Alice"))), []int64{1, 4}},
+        {"and of or", group(pagerpb.FilterLogic_FILTER_LOGIC_UNSPECIFIED,
+            cond("created_at", pagerpb.FilterOp_FILTER_OP_GT, intVal(1000)),
+            group(pagerpb.FilterLogic_FILTER_LOGIC_OR,
+                cond("score", pagerpb.FilterOp_FILTER_OP_EQ, intVal(95)),
+                cond("score", pagerpb.FilterOp_FILTER_OP_EQ, intVal(80)))), []int64{3, 4}},
+    } {
+        t.Run(tc.name, func(t *testing.T) {
+            var rows []TestModel
+            in := &pagerpb.Page{Order: []*pagerpb.Order{{Key: "id", Asc: true}}, Filter: tc.filter}
+            out, err := pg.ApplyAndScan(context.Background(), db.NewSelect().Model((*TestModel)(nil)), in, &rows)
+            if err != nil { t.Fatal(err) }
+            if !sameIDs(ids(rows), tc.want) {
+                t.Fatalf("expected %v, got %v", tc.want, ids(rows))
+            }
+            if out.Filter != tc.filter {
+                t.Fatal("response must echo the filter")
+            }
+        })
+    }
+}
+
+func TestApplyAndScan_FilterInvalid(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    deep := cond("id", pagerpb.FilterOp_FILTER_OP_EQ, intVal(1))
+    for i := 0; i <= maxFilterDepth; i++ {
+        deep = group(pagerpb.FilterLogic_FILTER_LOGIC_AND, deep)
+    }
+
+    for _, tc := range []struct {
+        name   string
+        opts   Options
+        filter *pagerpb.Filter
+    }{
+        {"unknown field", Options{}, cond("nope", pagerpb.FilterOp_FILTER_OP_EQ, intVal(1))},
+        {"not allowed", Options{AllowedFilterKeys: []string{"name"}}, cond("score", pagerpb.FilterOp_FILTER_OP_EQ, intVal(1))},
+        {"strict raw column", Options{StrictKeys: true}, cond("score", pagerpb.FilterOp_FILTER_OP_EQ, intVal(1))},
+        {"type mismatch", Options{}, cond("score", pagerpb.FilterOp_FILTER_OP_EQ, strVal("high"))},
+        {"fractional int", Options{}, cond("score", pagerpb.FilterOp_FILTER_OP_EQ, &pagerpb.FilterValue{Kind: &pagerpb.FilterValue_DoubleValue{DoubleValue: 1.5}})},
+        {"bool for string", Options{}, cond("name", pagerpb.FilterOp_FILTER_OP_EQ, boolVal(true))},
+        {"missing value", Options{}, cond("score", pagerpb.FilterOp_FILTER_OP_GT)},
+        {"like on int", Options{}, cond("score", pagerpb.FilterOp_FILTER_OP_LIKE, strVal("9%"))},
+        {"empty in", Options{}, cond("score", pagerpb.FilterOp_FILTER_OP_IN)},
+        {"is_null string", Options{}, cond("name", pagerpb.FilterOp_FILTER_OP_IS_NULL, strVal("yes"))},
+        {"unknown op", Options{}, cond("score", pagerpb.FilterOp(99), intVal(1))},
+        {"empty group", Options{}, group(pagerpb.FilterLogic_FILTER_LOGIC_AND)},
+        {"empty nested filter", Options{}, group(pagerpb.FilterLogic_FILTER_LOGIC_AND, &pagerpb.Filter{})},
+        {"too deep", Options{}, deep},
+    } {
+        t.Run(tc.name, func(t *testing.T) {
+            opts := tc.opts
+            opts.LogLevel = "error"
+            var rows []TestModel
+            _, err := New(&opts).ApplyAndScan(context.Background(), db.NewSelect().Model((*TestModel)(nil)), &pagerpb.Page{Filter: tc.filter}, &rows)
+            expectInvalidRequest(t, err)
+        })
+    }
+}
+
+func TestApplyAndScan_FilterNullsAndRelations(t *testing.T) {
+    ctx := context.Background()
+    pg := New(&Options{DefaultLimit: 10, MaxLimit: 10, LogLevel: "error"})
+    order := []*pagerpb.Order{{Key: "id", Asc: true}}
+
+    ndb := setupNullableDB(t)
+    defer ndb.Close()
+    for _, tc := range []struct {
+        filter *pagerpb.Filter
+        want   []int64
+    }{
+        {cond("published_at", pagerpb.FilterOp_FILTER_OP_IS_NULL), []int64{2, 4, 7}},
+        {cond("published_at", pagerpb.FilterOp_FILTER_OP_IS_NULL, boolVal(false)), []int64{1, 3, 5, 6, 8}},
+        {cond("rank", pagerpb.FilterOp_FILTER_OP_EQ, intVal(0)), []int64{1, 7}},
+    } {
+        var rows []nullableModel
+        if _, err := pg.ApplyAndScan(ctx, ndb.NewSelect().Model((*nullableModel)(nil)), &pagerpb.Page{Order: order, Filter: tc.filter}, &rows); err != nil {
+            t.Fatal(err)
+        }
+        if !sameIDs(nullableIDs(rows), tc.want) {
+            t.Fatalf("expected %v, got %v", tc.want, nullableIDs(rows))
+        }
+    }
+
+    // Relation columns are joined on demand
+    bdb := setupBooksDB(t)
+    defer bdb.Close()
+    var books []joinBook
+    in := &pagerpb.Page{Order: order, Filter: cond("author.name", pagerpb.FilterOp_FILTER_OP_EQ, strVal("Ann"))}
+    if _, err := pg.ApplyAndScan(ctx, bdb.NewSelect().Model((*joinBook)(nil)), in, &books); err != nil {
+        t.Fatal(err)
+    }
+    if len(books) != 2 || books[0].ID != 2 || books[1].ID != 4 {
+        t.Fatalf("unexpected books %+v", books)
+    }
+}
+
+func TestApplyAndScan_FilterTotalAndWalk(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    pg := New(&Options{DefaultLimit: 1, MaxLimit: 10, LogLevel: "error"})
+    in := &pagerpb.Page{
+        Order:        []*pagerpb.Order{{Key: "score", Asc: true}},
+        Filter:       cond("score", pagerpb.FilterOp_FILTER_OP_GTE, intVal(88)),
+        IncludeTotal: true,
+    }
+
+    res, err := Paginate[TestModel](ctx, pg, db.NewSelect().Model((*TestModel)(nil)), in)
+    if err != nil { t.Fatal(err) }
+    if res.PageInfo.TotalCount != 3 || res.PageInfo.TotalPages != 3 {
+        t.Fatalf("total must count filtered rows, got %+v", res.PageInfo)
+    }
+
+    var got []int64
+    for m, err := range All[TestModel](ctx, pg, db.NewSelect().Model((*TestModel)(nil)), in, IterOptions{}) {
+        if err != nil { t.Fatal(err) }
+        got = append(got, m.ID)
+    }
+    if !sameIDs(got, []int64{5, 1, 3}) {
+        t.Fatalf("unexpected filtered walk %v", got)
+    }
+}
@@ -0,0 +1,32 @@
+package pager
+
+import (
+    "context"
+    "testing"
+
+    pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+    "github.com/uptrace/bun"
+)
+
+// walkPages follows next cursors (or prev cursors when backward) and returns every row visited.
+func walkPages[T any](t *testing.T, pg *Pager, newQuery func() *bun.SelectQuery, order []*pagerpb.Order, limit uint32, backward bool) []T {
+    t.Helper()
+    var all []T
+    cursor := ""
+    for i := 0; i < 20; i++ {
+        var batch []T
+        in := &pagerpb.Page{Limit: limit, Order: order, Selector: &pagerpb.Page_Cursor{Cursor: cursor}}
+        if backward { in.Direction = pagerpb.Direction_DIRECTION_BEFORE }
+        out, err := pg.ApplyAndScan(context.Background(), newQuery(), in, &batch)
+        if err != nil { t.Fatal(err) }
+        if backward {
+            all = append(batch, all...)
+            if cursor = out.PrevCursor; cursor == "" { return all }
+            continue
+        }
+        all = append(all, batch...)
+        if cursor = out.GetCursor(); cursor == "" { return all }
+    }
+    t.Fatal("walk did not terminate")
+    return nil
+}
@@ -1,17 +1,38 @@
 package pager
 
 import (
+    "database/sql/driver"
     "reflect"
     "strings"
     "sync"
+
+    "github.com/jinzhu/inflection"
+    "github.com/uptrace/bun"
 )
 
 type ModelInfo struct {
     TableName    string
+    // TableAlias is the alias bun selects the table under (`bun:"alias:..."`, else the
+    // snake_case type name).
+    TableAlias   string
     PKColumns    []string
     KeyToColumn  map[string]string
-    // FieldIndexByColumn maps bun column name -> struct field index
-    FieldIndexByColumn map[string]int
+    // KeyAliases maps logical keys declared with `page:"<key>"` tags to their columns.
+    // Aliases are also present in KeyToColumn.
+    KeyAliases map[string]string
+    // FieldIndexByColumn maps bun column name -> struct field index path
+    // (reflect.Value.FieldByIndex), descending into embedded structs
+    FieldIndexByColumn map[string][]int
+    // ColumnTypes maps model and relation columns to their Go field types
+    ColumnTypes map[string]reflect.Type
+    // NullableColumns marks columns whose field can hold NULL (pointers, sql.Null* types)
+    NullableColumns map[string]bool
+    // Relations maps the join alias of a has-one/belongs-to relation to the Go field
+    // name passed to SelectQuery.Relation (e.g. "author" -> "Author").
+    Relations map[string]string
+    // RelationFieldPaths maps relation column keys ("<join alias>.<column>") to the
+    // field index path from the model struct (relation field, then related field).
+    RelationFieldPaths map[string][]int
 }
 
 var modelInfoCache sync.Map // map[reflect.Type]*ModelInfo
@@ -26,51 +47,284 @@ func InferModelInfo(model interface{}) (*ModelInfo, error) {
 
     info := &ModelInfo{
         KeyToColumn:        make(map[string]string),
-        FieldIndexByColumn: make(map[string]int),
+        KeyAliases:         make(map[string]string),
+        FieldIndexByColumn: make(map[string][]int),
+        ColumnTypes:        make(map[string]reflect.Type),
+        NullableColumns:    make(map[string]bool),
+        Relations:          make(map[string]string),
+        RelationFieldPaths: make(map[string][]int),
+    }
+
+    info.TableName = tableNameFor(t)
+    info.TableAlias = tableAliasFor(t)
+
+    columns := structColumns(t, nil, "", func(field reflect.StructField, path []int, parts []string) {
+        // Relation field: only joined relations contribute orderable columns
+        if kind, _ := relationKind(parts); kind == "has-one" || kind == "belongs-to" {
+            addRelationColumns(info, field, path, parts)
+        }
+    })
+    for _, c := range columns {
+        // Logical key equals bun column name; a `page` tag adds a client-facing alias
+        info.KeyToColumn[c.column] = c.column
+        if key := c.field.Tag.Get("page"); key != "" && key != "-" {
+            info.KeyAliases[key] = c.column
+            info.KeyToColumn[key] = c.column
+        }
+        info.FieldIndexByColumn[c.column] = c.path
+        info.ColumnTypes[c.column] = c.field.Type
+        if isNullableType(c.field.Type) {
+            info.NullableColumns[c.column] = true
+        }
+
+        for _, part := range c.parts {
+            if part == "pk" {
+                info.PKColumns = append(info.PKColumns, c.column)
+            }
+        }
+    }
+
+    if len(info.PKColumns) == 0 {
+        info.PKColumns = []string{"id"}
+        info.KeyToColumn["id"] = "id"
+    }
+    modelInfoCache.Store(t, info)
+    return info, nil
+}
+
+var baseModelType = reflect.TypeOf(bun.BaseModel{})
+
+// columnField is a bun column found while walking a model struct.
+type columnField struct {
+    column string
+    path   []int
+    field  reflect.StructField
+    parts  []string // bun tag name and options
+}
+
+// structColumns lists the tagged bun columns of t in declaration order, descending like bun
+// into anonymous embedded structs (including `bun:",extend"`) and `embed:<prefix>` fields.
+// Paths are prefixed with path and column names with prefix. Relation fields are passed to
+// rel instead. When embedding repeats a column, the shallowest field wins, as in bun.
+func structColumns(t reflect.Type, path []int, prefix string, rel func(field reflect.StructField, path []int, parts []string)) []columnField {
+    var all []columnField
+    collectColumns(t, path, prefix, rel, &all)
+
+    best := map[string]int{}
+    for i, c := range all {
+        if j, ok := best[c.column]; !ok || len(c.path) < len(all[j].path) {
+            best[c.column] = i
+        }
+    }
+    out := make([]columnField, 0, len(best))
+    for i, c := range all {
+        if best[c.column] == i {
+            out = append(out, c)
+        }
     }
+    return out
+}
 
+func collectColumns(t reflect.Type, path []int, prefix string, rel func(field reflect.StructField, path []int, parts []string), out *[]columnField) {
     for i := 0; i < t.NumField(); i++ {
         field := t.Field(i)
         bunTag := field.Tag.Get("bun")
-        if bunTag == "" {
+        if bunTag == "-" || field.Type == baseModelType {
             continue
         }
-
+        fieldPath := append(append(make([]int, 0, len(path)+1), path...), i)
         parts := strings.Split(bunTag, ",")
+
+        if field.Anonymous {
+            if et := indirectStruct(field.Type); et != nil {
+                collectColumns(et, fieldPath, prefix, rel, out)
+            }
+            continue
+        }
+        if !field.IsExported() {
+            continue
+        }
+        if embedPrefix, ok := tagOption(parts, "embed"); ok {
+            if et := indirectStruct(field.Type); et != nil {
+                collectColumns(et, fieldPath, prefix+embedPrefix, rel, out)
+            }
+            continue
+        }
+        if _, ok := relationKind(parts); ok {
+            if rel != nil {
+                rel(field, fieldPath, parts)
+            }
+            continue
+        }
         columnName := parts[0]
-        if columnName == "" {
+        if columnName == "" || strings.Contains(columnName, ":") {
             // No implicit snake_case fallback: column must be specified in bun tag
             continue
         }
+        *out = append(*out, columnField{column: prefix + columnName, path: fieldPath, field: field, parts: parts})
+    }
+}
 
-        // Logical key equals bun column name
-        info.KeyToColumn[columnName] = columnName
-        info.FieldIndexByColumn[columnName] = i
+// indirectStruct returns t (or the type t points to) when it is a struct, else nil.
+func indirectStruct(t reflect.Type) reflect.Type {
+    if t.Kind() == reflect.Ptr {
+        t = t.Elem()
+    }
+    if t.Kind() != reflect.Struct {
+        return nil
+    }
+    return t
+}
 
-        for _, part := range parts {
-            if part == "pk" {
-                info.PKColumns = append(info.PKColumns, columnName)
+// tagOption returns the value of a "<name>:<value>" bun tag option.
+func tagOption(parts []string, name string) (string, bool) {
+    for _, part := range parts {
+        if v, ok := strings.CutPrefix(part, name+":"); ok {
+            return v, true
+        }
+    }
+    return "", false
+}
+
+// hasTagOption reports whether a bun tag carries a bare option (e.g. "extend").
+func hasTagOption(parts []string, name string) bool {
+    for _, part := range parts[1:] {
+        if part == name {
+            return true
+        }
+    }
+    return false
+}
+
+// relationKind returns the rel:/m2m: kind of a bun tag, if any.
+func relationKind(parts []string) (string, bool) {
+    for _, part := range parts {
+        if kind, ok := strings.CutPrefix(part, "rel:"); ok {
+            return kind, true
+        }
+        if strings.HasPrefix(part, "m2m:") {
+            return "m2m", true
+        }
+    }
+    return "", false
+}
+
+// addRelationColumns records the tagged columns of a has-one/belongs-to relation under
+// "<join alias>.<column>" keys. The alias is the one bun joins with: the tag name, else
+// the snake_case field name. Relation columns are nullable (LEFT JOIN).
+func addRelationColumns(info *ModelInfo, field reflect.StructField, path []int, parts []string) {
+    alias := parts[0]
+    if alias == "" || strings.Contains(alias, ":") {
+        alias = underscore(field.Name)
+    }
+    rt := indirectStruct(field.Type)
+    if rt == nil {
+        return
+    }
+    info.Relations[alias] = field.Name
+    // Nested relations are not joined: skip them
+    for _, c := range structColumns(rt, path, alias+".", nil) {
+        info.KeyToColumn[c.column] = c.column
+        info.RelationFieldPaths[c.column] = c.path
+        info.ColumnTypes[c.column] = c.field.Type
+        info.NullableColumns[c.column] = true
+    }
+}
+
+// relationAlias returns the join alias of a relation column key ("author.name" -> "author").
+func relationAlias(column string) (string, bool) {
+    alias, _, ok := strings.Cut(column, ".")
+    return alias, ok
+}
+
+var valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+
+// isNullableType reports whether a field of type t can carry NULL: pointers and
+// sql.Null*-style structs (a driver.Valuer with a bool Valid field).
+func isNullableType(t reflect.Type) bool {
+    if t.Kind() == reflect.Ptr {
+        return true
+    }
+    if t.Kind() != reflect.Struct || !t.Implements(valuerType) {
+        return false
+    }
+    f, ok := t.FieldByName("Valid")
+    return ok && f.Type.Kind() == reflect.Bool
+}
+
+// tableNameFor resolves the table name the way bun does: `bun:"table:..."` (or a bare
+// name) on the embedded bun.BaseModel, or the table of an embedded struct tagged
+// `bun:",extend"`, otherwise the pluralized snake_case type name.
+func tableNameFor(t reflect.Type) string {
+    name := ""
+    for i := 0; i < t.NumField(); i++ {
+        field := t.Field(i)
+        parts := strings.Split(field.Tag.Get("bun"), ",")
+        if field.Type != baseModelType {
+            if et := indirectStruct(field.Type); field.Anonymous && et != nil &&
+                (hasTagOption(parts, "extend") || hasTagOption(parts, "inherit")) {
+                name = tableNameFor(et)
+            }
+            continue
+        }
+        for j, part := range parts {
+            if v, ok := strings.CutPrefix(part, "table:"); ok && v != "" {
+                name = v
+                break
+            }
+            if j == 0 && part != "" && !strings.Contains(part, ":") {
+                name = part
+                break
             }
         }
     }
+    if name == "" {
+        name = inflection.Plural(underscore(t.Name()))
+    }
+    return name
+}
 
-    if len(info.PKColumns) == 0 {
-        info.PKColumns = []string{"id"}
-        info.KeyToColumn["id"] = "id"
+// tableAliasFor resolves the alias bun gives the table: `bun:"alias:..."` on the embedded
+// bun.BaseModel, otherwise the snake_case type name.
+func tableAliasFor(t reflect.Type) string {
+    for i := 0; i < t.NumField(); i++ {
+        field := t.Field(i)
+        if field.Type != baseModelType {
+            continue
+        }
+        if alias, ok := tagOption(strings.Split(field.Tag.Get("bun"), ","), "alias"); ok && alias != "" {
+            return alias
+        }
     }
-    if len(info.PKColumns) > 1 {
-        return nil, NewInvalidRequestError("composite primary key is not supported")
+    return underscore(t.Name())
+}
+
+// underscore converts a Go identifier to snake_case (same rules as bun's internal.Underscore).
+func underscore(s string) string {
+    r := make([]byte, 0, len(s)+5)
+    for i := 0; i < len(s); i++ {
+        c := s[i]
+        if c >= 'A' && c <= 'Z' {
+            if i > 0 && i+1 < len(s) && (isLower(s[i-1]) || isLower(s[i+1])) {
+                r = append(r, '_', c+32)
+            } else {
+                r = append(r, c+32)
+            }
+        } else {
+            r = append(r, c)
+        }
     }
-    modelInfoCache.Store(t, info)
-    return info, nil
+    return string(r)
 }
 
-// firstPKColumn returns the single primary key column or "id" as a safe default.
-// InferModelInfo guarantees at most one PK and ensures default to "id" when missing,
-// but this helper centralizes the fallback to avoid repetition.
-func firstPKColumn(info *ModelInfo) string {
+func isLower(c byte) bool { return c >= 'a' && c <= 'z' }
+
+// pkColumns returns the primary key columns in declaration order, or ["id"] as a safe default.
+// InferModelInfo already defaults to "id" when no PK is tagged, but this helper
+// centralizes the fallback for hand-built ModelInfo values.
+func pkColumns(info *ModelInfo) []string {
     if info != nil && len(info.PKColumns) > 0 {
-        return info.PKColumns[0]
+        return info.PKColumns
     }
-    return "id"
+    return []string{"id"}
 }
@@ -7,10 +7,10 @@ type cm struct {
     K2 int64 `bun:"k2,pk"`
 }
 
-func TestInferModelInfo_CompositePKNotSupported(t *testing.T) {
-    _, err := InferModelInfo(&cm{})
-    if err == nil {
-        t.Fatal("expected error for composite primary key")
+func TestInferModelInfo_CompositePK(t *testing.T) {
+    info, err := InferModelInfo(&cm{})
+    if err != nil { t.Fatal(err) }
+    if len(info.PKColumns) != 2 || info.PKColumns[0] != "k1" || info.PKColumns[1] != "k2" {
+        t.Fatalf("expected PK columns [k1 k2] in declaration order, got %v", info.PKColumns)
     }
 }
-
@@ -437,13 +437,9 @@ func TestCursorWhere(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		expected := "((created_at > ?) OR (created_at = ? AND id > ?))"
-		if where != expected {
-			t.Errorf("expected WHERE %s, got %s", expected, where)
-		}
-
-		if len(args) != 3 {
-			t.Errorf("expected 3 args, got %d", len(args))
+		expected := `((("test_model"."created_at" > 1000) OR ("test_model"."created_at" = 1000 AND "test_model"."id" > 5)))`
+		if got := renderWhere(t, where, args); got != expected {
+			t.Errorf("expected WHERE %s, got %s", expected, got)
 		}
 	})
 
@@ -470,16 +466,13 @@ func TestCursorWhere(t *testing.T) {
 		}
 
 		// Should build: (score < ?) OR (score = ? AND name > ?) OR (score = ? AND name = ? AND id > ?)
-        if !strings.Contains(where, "score < ?") {
-            t.Error("expected 'score < ?' in WHERE clause")
+        got := renderWhere(t, where, args)
+        if !strings.Contains(got, `"test_model"."score" < 90`) {
+            t.Error("expected 'score < 90' in WHERE clause")
         }
-        if !strings.Contains(where, "score = ? AND name > ?") {
-            t.Error("expected 'score = ? AND name > ?' in WHERE clause")
+        if !strings.Contains(got, `"test_model"."score" = 90 AND "test_model"."name" > 'Bob'`) {
+            t.Error("expected 'score = 90 AND name > 'Bob'' in WHERE clause")
         }
-
-		if len(args) != 6 {
-			t.Errorf("expected 6 args, got %d", len(args))
-		}
 	})
 }
 
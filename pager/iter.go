@@ -0,0 +1,104 @@
+package pager
+
+import (
+    "context"
+    "iter"
+
+    pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+    "github.com/uptrace/bun"
+)
+
+// IterOptions configures Pages and All.
+type IterOptions struct {
+    // BatchSize is the number of rows fetched per page (default: the request limit,
+    // then Options.DefaultLimit; clamped by Options.MaxLimit).
+    BatchSize int
+    // MaxRows stops the walk after this many rows (0: no limit).
+    MaxRows int
+}
+
+// Pages walks the whole keyset forward page by page, starting at in's cursor (or the
+// beginning) under in's order. Each page runs on a clone of q. Cursors are self-contained
+// and pinned to the OrderPlan, so rows inserted or deleted during the walk neither cause
+// duplicates nor skip rows that existed before it (rows inserted behind the position are
+// not visited). The context is checked between pages; its error ends the walk.
+// Offset pages and DIRECTION_BEFORE are rejected with INVALID_REQUEST.
+func Pages[T any](ctx context.Context, p *Pager, q *bun.SelectQuery, in *pagerpb.Page, opts IterOptions) iter.Seq2[*Result[T], error] {
+    return func(yield func(*Result[T], error) bool) {
+        if in == nil {
+            in = &pagerpb.Page{}
+        }
+        if _, hasPage := detectPresence(in); hasPage || in.GetDirection() == pagerpb.Direction_DIRECTION_BEFORE {
+            yield(nil, NewInvalidRequestError("iteration requires cursor mode walking forward"))
+            return
+        }
+        walker := p.withSelfContainedCursor()
+        batch := uint32(opts.BatchSize)
+        if batch == 0 {
+            batch = in.GetLimit()
+        }
+        limit, _ := normalizeLimit(batch, p.opts)
+        _, cursor := getPageAndCursor(in)
+        rows := 0
+        for first := true; ; first = false {
+            if err := ctx.Err(); err != nil {
+                yield(nil, err)
+                return
+            }
+            pageLimit := limit
+            if opts.MaxRows > 0 {
+                if rows >= opts.MaxRows {
+                    return
+                }
+                pageLimit = min(pageLimit, opts.MaxRows-rows)
+            }
+            page := &pagerpb.Page{Limit: uint32(pageLimit), Order: in.Order, OrderBy: in.OrderBy, Filter: in.Filter, Selector: &pagerpb.Page_Cursor{Cursor: cursor}}
+            if first {
+                page.IncludeTotal, page.TotalMode = in.IncludeTotal, in.TotalMode
+            }
+            res, err := Paginate[T](ctx, walker, q.Clone(), page)
+            if err != nil {
+                yield(nil, err)
+                return
+            }
+            rows += len(res.Items)
+            if len(res.Items) > 0 && !yield(res, nil) {
+                return
+            }
+            if !res.HasMore {
+                return
+            }
+            cursor = res.NextCursor
+        }
+    }
+}
+
+// All walks the whole keyset row by row; see Pages. A failing page yields the zero T
+// with the error and ends the walk.
+func All[T any](ctx context.Context, p *Pager, q *bun.SelectQuery, in *pagerpb.Page, opts IterOptions) iter.Seq2[T, error] {
+    return func(yield func(T, error) bool) {
+        for res, err := range Pages[T](ctx, p, q, in, opts) {
+            if err != nil {
+                var zero T
+                yield(zero, err)
+                return
+            }
+            for _, item := range res.Items {
+                if !yield(item, nil) {
+                    return
+                }
+            }
+        }
+    }
+}
+
+// withSelfContainedCursor returns p, or a pager sharing its options and logger whose
+// cursors carry every order column value (no anchor fetch, no STALE_CURSOR on deletes).
+func (p *Pager) withSelfContainedCursor() *Pager {
+    if p.opts.SelfContainedCursor {
+        return p
+    }
+    opts := *p.opts
+    opts.SelfContainedCursor = true
+    return &Pager{opts: &opts, logger: p.logger}
+}
@@ -0,0 +1,100 @@
+package pager
+
+import (
+    "context"
+    "errors"
+    "testing"
+
+    pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+)
+
+func TestAll_WalksKeyset(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error"})
+    in := &pagerpb.Page{Order: []*pagerpb.Order{{Key: "score", Asc: true}}}
+
+    var got []int64
+    for m, err := range All[TestModel](ctx, pg, db.NewSelect().Model((*TestModel)(nil)), in, IterOptions{}) {
+        if err != nil { t.Fatal(err) }
+        got = append(got, m.ID)
+    }
+    if !sameIDs(got, []int64{4, 2, 5, 1, 3}) {
+        t.Fatalf("unexpected walk %v", got)
+    }
+
+    // Batch size and max rows
+    var sizes []int
+    for res, err := range Pages[TestModel](ctx, pg, db.NewSelect().Model((*TestModel)(nil)), in, IterOptions{BatchSize: 3, MaxRows: 4}) {
+        if err != nil { t.Fatal(err) }
+        sizes = append(sizes, len(res.Items))
+    }
+    if len(sizes) != 2 || sizes[0] != 3 || sizes[1] != 1 {
+        t.Fatalf("expected pages of 3 and 1 rows, got %v", sizes)
+    }
+
+    // Breaking out stops fetching
+    n := 0
+    for range All[TestModel](ctx, pg, db.NewSelect().Model((*TestModel)(nil)), in, IterOptions{}) {
+        if n++; n == 3 { break }
+    }
+    if n != 3 { t.Fatalf("expected 3 rows before break, got %d", n) }
+}
+
+func TestAll_ConcurrentWrites(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error"})
+    in := &pagerpb.Page{Order: []*pagerpb.Order{{Key: "created_at", Asc: true}}}
+
+    var got []int64
+    seen := map[int64]bool{}
+    for m, err := range All[TestModel](ctx, pg, db.NewSelect().Model((*TestModel)(nil)), in, IterOptions{}) {
+        if err != nil { t.Fatal(err) }
+        if seen[m.ID] { t.Fatalf("row %d visited twice", m.ID) }
+        seen[m.ID] = true
+        got = append(got, m.ID)
+        if m.ID == 2 {
+            // Writes during the walk: the row just seen is deleted (the next page's anchor),
+            // one row lands behind the position and one ahead of it
+            if _, err := db.NewDelete().Model((*TestModel)(nil)).Where("id = ?", 2).Exec(ctx); err != nil { t.Fatal(err) }
+            if _, err := db.NewInsert().Model(&[]TestModel{{Name: "behind", CreatedAt: 1500}, {Name: "ahead", CreatedAt: 4500}}).Exec(ctx); err != nil {
+                t.Fatal(err)
+            }
+        }
+    }
+    // 1..5 in created_at order, plus the row inserted ahead (id 7); the one behind (id 6) is not visited
+    if !sameIDs(got, []int64{1, 2, 3, 4, 7, 5}) {
+        t.Fatalf("unexpected walk under concurrent writes %v", got)
+    }
+}
+
+func TestPages_CancellationAndInvalidRequests(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error"})
+
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+    pages, lastErr := 0, error(nil)
+    for res, err := range Pages[TestModel](ctx, pg, db.NewSelect().Model((*TestModel)(nil)), nil, IterOptions{}) {
+        if err != nil { lastErr = err; break }
+        pages++
+        if len(res.Items) == 0 { t.Fatal("empty page yielded") }
+        cancel()
+    }
+    if pages != 1 || !errors.Is(lastErr, context.Canceled) {
+        t.Fatalf("expected cancellation after the first page, got %d pages, err %v", pages, lastErr)
+    }
+
+    for _, in := range []*pagerpb.Page{
+        {Selector: &pagerpb.Page_Page{Page: 1}},
+        {Direction: pagerpb.Direction_DIRECTION_BEFORE},
+    } {
+        for _, err := range All[TestModel](context.Background(), pg, db.NewSelect().Model((*TestModel)(nil)), in, IterOptions{}) {
+            expectInvalidRequest(t, err)
+        }
+    }
+}
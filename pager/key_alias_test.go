@@ -0,0 +1,111 @@
+package pager
+
+import (
+    "context"
+    "strings"
+    "testing"
+
+    pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+    "github.com/uptrace/bun"
+)
+
+type aliasedModel struct {
+    bun.BaseModel `bun:"table:test_models"`
+    ID        int64  `bun:"id,pk,autoincrement" page:"id"`
+    Name      string `bun:"name"`
+    CreatedAt int64  `bun:"created_at" page:"createdAt"`
+    Score     int    `bun:"score" page:"-"`
+}
+
+func aliasedIDs(rows []aliasedModel) []int64 {
+    out := make([]int64, len(rows))
+    for i, r := range rows { out[i] = r.ID }
+    return out
+}
+
+func TestInferModelInfo_PageTagAliases(t *testing.T) {
+    info, err := InferModelInfo(&aliasedModel{})
+    if err != nil { t.Fatal(err) }
+    if info.KeyAliases["createdAt"] != "created_at" || info.KeyToColumn["createdAt"] != "created_at" {
+        t.Fatalf("expected createdAt alias, got %v", info.KeyAliases)
+    }
+    if _, ok := info.KeyAliases["-"]; ok || len(info.KeyAliases) != 2 {
+        t.Fatalf("unexpected aliases %v", info.KeyAliases)
+    }
+}
+
+func TestBuildOrderPlan_KeyAliases(t *testing.T) {
+    info, err := InferModelInfo(&aliasedModel{})
+    if err != nil { t.Fatal(err) }
+    order := func(key string) []OrderSpecInterface { return []OrderSpecInterface{&pagerpb.Order{Key: key, Asc: true}} }
+
+    // Tag alias and Options alias resolve to the internal column
+    opts := &Options{KeyAliases: map[string]string{"displayName": "name"}}
+    for key, column := range map[string]string{"createdAt": "created_at", "displayName": "name"} {
+        plan, err := buildOrderPlan(order(key), info, opts)
+        if err != nil { t.Fatal(err) }
+        if plan.Items[0].Column != column { t.Fatalf("%s: expected column %s, got %+v", key, column, plan.Items) }
+    }
+    // Raw columns still work outside strict mode
+    if _, err := buildOrderPlan(order("score"), info, opts); err != nil {
+        t.Fatalf("raw column rejected without strict mode: %v", err)
+    }
+
+    // Strict mode: only aliases
+    opts.StrictKeys = true
+    for _, key := range []string{"created_at", "score", "name"} {
+        _, err := buildOrderPlan(order(key), info, opts)
+        if pe, ok := err.(*PagerError); !ok || pe.Code != ErrCodeInvalidRequest {
+            t.Fatalf("%s: expected INVALID_REQUEST in strict mode, got %v", key, err)
+        }
+    }
+    if _, err := buildOrderPlan(order("displayName"), info, opts); err != nil {
+        t.Fatalf("alias rejected in strict mode: %v", err)
+    }
+    // Empty key (PK order) is not a column name
+    if _, err := buildOrderPlan(order(""), info, opts); err != nil {
+        t.Fatalf("empty key rejected in strict mode: %v", err)
+    }
+
+    // An alias to an unknown column is a configuration error, not a client error
+    _, err = buildOrderPlan(order("bad"), info, &Options{KeyAliases: map[string]string{"bad": "nope"}})
+    if _, ok := err.(*PagerError); err == nil || ok {
+        t.Fatalf("expected configuration error, got %v", err)
+    }
+}
+
+func TestApplyAndScan_OrderByAliasAndColumn(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error"})
+
+    // An alias and its column name the same field: rejected, not silently collapsed
+    var rows []aliasedModel
+    _, err := pg.ApplyAndScan(context.Background(), db.NewSelect().Model((*aliasedModel)(nil)), &pagerpb.Page{OrderBy: "createdAt, created_at desc"}, &rows)
+    pe, ok := err.(*PagerError)
+    if !ok || pe.Code != ErrCodeInvalidRequest || !strings.Contains(pe.Message, `"created_at"`) {
+        t.Fatalf("expected INVALID_REQUEST naming created_at, got %v", err)
+    }
+}
+
+func TestApplyAndScan_StrictKeyAliasWalk(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error", StrictKeys: true})
+    order := []*pagerpb.Order{{Key: "createdAt", Asc: true}}
+
+    all := walkPages[aliasedModel](t, pg, func() *bun.SelectQuery { return db.NewSelect().Model((*aliasedModel)(nil)) }, order, 2, false)
+    if !sameIDs(aliasedIDs(all), []int64{1, 2, 3, 4, 5}) {
+        t.Fatalf("unexpected walk %v", aliasedIDs(all))
+    }
+
+    var rows []aliasedModel
+    out, err := pg.ApplyAndScan(ctx, db.NewSelect().Model((*aliasedModel)(nil)), &pagerpb.Page{Limit: 2, Order: order}, &rows)
+    if err != nil { t.Fatal(err) }
+    if out.Order[0].GetKey() != "createdAt" { t.Fatalf("expected the client key echoed, got %v", out.Order) }
+    _, err = pg.ApplyAndScan(ctx, db.NewSelect().Model((*aliasedModel)(nil)), &pagerpb.Page{Order: []*pagerpb.Order{{Key: "created_at"}}}, &rows)
+    if pe, ok := err.(*PagerError); !ok || pe.Code != ErrCodeInvalidRequest {
+        t.Fatalf("expected raw column rejected, got %v", err)
+    }
+}
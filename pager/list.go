@@ -0,0 +1,188 @@
+package pager
+
+import (
+    "context"
+    "fmt"
+
+    pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+    "github.com/uptrace/bun"
+    "google.golang.org/protobuf/proto"
+    "google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// AIP-158 field names read from List requests and written to List responses.
+const (
+    listPageSize      = "page_size"
+    listPageToken     = "page_token"
+    listFilter        = "filter"
+    listOrderBy       = "order_by"
+    listNextPageToken = "next_page_token"
+    listTotalSize     = "total_size"
+)
+
+// ListOptions configures ApplyAndScanList.
+type ListOptions struct {
+    // IncludeTotal fills the response's total_size (when it has one) with TotalMode.
+    IncludeTotal bool
+    TotalMode    pagerpb.TotalMode
+}
+
+// ApplyAndScanList pages q for an AIP-158 List request and fills the List response, so
+// services keep their page_size/page_token/next_page_token wire format. The request
+// provides page_size (0: DefaultLimit, negative: INVALID_REQUEST, clamped by MaxLimit),
+// page_token (a cursor; "" starts from the beginning) and optionally filter (AIP-160, see
+// ApplyFilterString) and order_by (AIP-132). The response receives next_page_token
+// ("" on the last page) and, with IncludeTotal, total_size. Page tokens are bound to the
+// filter (see WithQueryFingerprint): replaying one with another filter fails with
+// CURSOR_QUERY_MISMATCH.
+//
+// Protobuf messages are read and written through protoreflect by those field names;
+// other types implement GetPageSize() int32 and GetPageToken() string (GetFilter() string
+// and GetOrderBy() string optional) and SetNextPageToken(string) (SetTotalSize(int32)
+// optional). The returned Page carries page_info.
+func (p *Pager) ApplyAndScanList(ctx context.Context, q *bun.SelectQuery, req, resp interface{}, dest interface{}, opts ListOptions) (*pagerpb.Page, error) {
+    size, ok := listInt(req, listPageSize)
+    if !ok {
+        return nil, NewInternalError(fmt.Sprintf("list request %T has no page_size", req))
+    }
+    token, ok := listString(req, listPageToken)
+    if !ok {
+        return nil, NewInternalError(fmt.Sprintf("list request %T has no page_token", req))
+    }
+    if size < 0 {
+        return nil, NewInvalidRequestError("page_size must not be negative")
+    }
+    orderBy, _ := listString(req, listOrderBy)
+    in := &pagerpb.Page{
+        Limit: uint32(min(size, 1<<32-1)), OrderBy: orderBy, Selector: &pagerpb.Page_Cursor{Cursor: token},
+        IncludeTotal: opts.IncludeTotal, TotalMode: opts.TotalMode,
+    }
+    if filter, _ := listString(req, listFilter); filter != "" {
+        var f *pagerpb.Filter
+        var err error
+        if q, f, err = p.applyFilterString(q, filter); err != nil {
+            return nil, err
+        }
+        // Page tokens are bound to the parsed filter (spacing and quoting do not matter)
+        if ctx, err = withFilterFingerprint(ctx, f); err != nil {
+            return nil, NewInternalError(fmt.Sprintf("failed to fingerprint query: %v", err))
+        }
+    }
+
+    out, err := p.ApplyAndScan(ctx, q, in, dest)
+    if err != nil {
+        return nil, err
+    }
+    if !setListField(resp, listNextPageToken, out.GetCursor()) {
+        return nil, NewInternalError(fmt.Sprintf("list response %T has no next_page_token", resp))
+    }
+    if opts.IncludeTotal {
+        setListField(resp, listTotalSize, int64(out.GetPageInfo().GetTotalCount()))
+    }
+    return out, nil
+}
+
+// Getters and setters of non-proto List messages.
+type (
+    pageSizeGetter      interface{ GetPageSize() int32 }
+    pageTokenGetter     interface{ GetPageToken() string }
+    filterGetter        interface{ GetFilter() string }
+    orderByGetter       interface{ GetOrderBy() string }
+    nextPageTokenSetter interface{ SetNextPageToken(string) }
+    totalSizeSetter     interface{ SetTotalSize(int32) }
+)
+
+// listField returns the descriptor of a proto message's field name, if any.
+func listField(msg interface{}, name string) (protoreflect.Message, protoreflect.FieldDescriptor) {
+    m, ok := msg.(proto.Message)
+    if !ok {
+        return nil, nil
+    }
+    rm := m.ProtoReflect()
+    fd := rm.Descriptor().Fields().ByName(protoreflect.Name(name))
+    if fd == nil || fd.IsList() || fd.IsMap() {
+        return nil, nil
+    }
+    return rm, fd
+}
+
+func listInt(msg interface{}, name string) (int64, bool) {
+    if rm, fd := listField(msg, name); fd != nil {
+        switch fd.Kind() {
+        case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+            protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+            return rm.Get(fd).Int(), true
+        case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+            return int64(min(rm.Get(fd).Uint(), uint64(1<<63-1))), true
+        }
+        return 0, false
+    }
+    if g, ok := msg.(pageSizeGetter); ok && name == listPageSize {
+        return int64(g.GetPageSize()), true
+    }
+    return 0, false
+}
+
+func listString(msg interface{}, name string) (string, bool) {
+    if rm, fd := listField(msg, name); fd != nil {
+        if fd.Kind() != protoreflect.StringKind {
+            return "", false
+        }
+        return rm.Get(fd).String(), true
+    }
+    switch name {
+    case listPageToken:
+        if g, ok := msg.(pageTokenGetter); ok {
+            return g.GetPageToken(), true
+        }
+    case listFilter:
+        if g, ok := msg.(filterGetter); ok {
+            return g.GetFilter(), true
+        }
+    case listOrderBy:
+        if g, ok := msg.(orderByGetter); ok {
+            return g.GetOrderBy(), true
+        }
+    }
+    return "", false
+}
+
+// setListField sets a string or integer field; it reports whether msg has the field.
+func setListField(msg interface{}, name string, v interface{}) bool {
+    if rm, fd := listField(msg, name); fd != nil {
+        switch val := v.(type) {
+        case string:
+            if fd.Kind() != protoreflect.StringKind {
+                return false
+            }
+            rm.Set(fd, protoreflect.ValueOfString(val))
+        case int64:
+            switch fd.Kind() {
+            case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+                rm.Set(fd, protoreflect.ValueOfInt32(int32(min(val, 1<<31-1))))
+            case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+                rm.Set(fd, protoreflect.ValueOfInt64(val))
+            case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+                rm.Set(fd, protoreflect.ValueOfUint32(uint32(min(val, 1<<32-1))))
+            case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+                rm.Set(fd, protoreflect.ValueOfUint64(uint64(val)))
+            default:
+                return false
+            }
+        }
+        return true
+    }
+    switch name {
+    case listNextPageToken:
+        if s, ok := msg.(nextPageTokenSetter); ok {
+            s.SetNextPageToken(v.(string))
+            return true
+        }
+    case listTotalSize:
+        if s, ok := msg.(totalSizeSetter); ok {
+            s.SetTotalSize(int32(min(v.(int64), 1<<31-1)))
+            return true
+        }
+    }
+    return false
+}
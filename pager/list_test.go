@@ -0,0 +1,142 @@
+package pager
+
+import (
+    "context"
+    "testing"
+
+    "github.com/uptrace/bun"
+    "google.golang.org/protobuf/proto"
+    "google.golang.org/protobuf/reflect/protodesc"
+    "google.golang.org/protobuf/reflect/protoreflect"
+    "google.golang.org/protobuf/types/descriptorpb"
+    "google.golang.org/protobuf/types/dynamicpb"
+)
+
+// listMessages builds AIP-158 ListRequest/ListResponse descriptors as a service would declare them.
+func listMessages(t *testing.T) (req, resp protoreflect.MessageDescriptor) {
+    t.Helper()
+    field := func(name string, n int32, typ descriptorpb.FieldDescriptorProto_Type) *descriptorpb.FieldDescriptorProto {
+        return &descriptorpb.FieldDescriptorProto{Name: proto.String(name), Number: proto.Int32(n), Type: typ.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()}
+    }
+    fdp := &descriptorpb.FileDescriptorProto{
+        Name:    proto.String("list_test.proto"),
+        Package: proto.String("pager.test"),
+        Syntax:  proto.String("proto3"),
+        MessageType: []*descriptorpb.DescriptorProto{
+            {Name: proto.String("ListModelsRequest"), Field: []*descriptorpb.FieldDescriptorProto{
+                field("parent", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+                field("page_size", 2, descriptorpb.FieldDescriptorProto_TYPE_INT32),
+                field("page_token", 3, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+                field("filter", 4, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+                field("order_by", 5, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+            }},
+            {Name: proto.String("ListModelsResponse"), Field: []*descriptorpb.FieldDescriptorProto{
+                field("next_page_token", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+                field("total_size", 3, descriptorpb.FieldDescriptorProto_TYPE_INT32),
+            }},
+        },
+    }
+    fd, err := protodesc.NewFile(fdp, nil)
+    if err != nil { t.Fatal(err) }
+    return fd.Messages().Get(0), fd.Messages().Get(1)
+}
+
+func TestApplyAndScanList_ProtoMessages(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    pg := New(&Options{DefaultLimit: 10, MaxLimit: 10, LogLevel: "error"})
+    reqDesc, respDesc := listMessages(t)
+    set := func(m *dynamicpb.Message, name string, v protoreflect.Value) {
+        m.Set(m.Descriptor().Fields().ByName(protoreflect.Name(name)), v)
+    }
+    get := func(m *dynamicpb.Message, name string) protoreflect.Value {
+        return m.Get(m.Descriptor().Fields().ByName(protoreflect.Name(name)))
+    }
+
+    req := dynamicpb.NewMessage(reqDesc)
+    set(req, "page_size", protoreflect.ValueOfInt32(2))
+    set(req, "filter", protoreflect.ValueOfString("score >= 85"))
+    set(req, "order_by", protoreflect.ValueOfString("score desc"))
+
+    var got []int64
+    for i := 0; i < 5; i++ {
+        resp := dynamicpb.NewMessage(respDesc)
+        var rows []TestModel
+        if _, err := pg.ApplyAndScanList(ctx, db.NewSelect().Model((*TestModel)(nil)), req, resp, &rows, ListOptions{IncludeTotal: true}); err != nil {
+            t.Fatal(err)
+        }
+        if get(resp, "total_size").Int() != 4 {
+            t.Fatalf("expected total_size 4, got %v", get(resp, "total_size"))
+        }
+        got = append(got, ids(rows)...)
+        token := get(resp, "next_page_token").String()
+        if token == "" { break }
+        set(req, "page_token", protoreflect.ValueOfString(token))
+    }
+    if !sameIDs(got, []int64{3, 1, 5, 2}) {
+        t.Fatalf("unexpected walk %v", got)
+    }
+
+    // Request errors surface as INVALID_REQUEST
+    for name, v := range map[string]protoreflect.Value{
+        "page_size":  protoreflect.ValueOfInt32(-1),
+        "page_token": protoreflect.ValueOfString("garbage"),
+        "filter":     protoreflect.ValueOfString("nope = 1"),
+        "order_by":   protoreflect.ValueOfString("score up"),
+    } {
+        bad := dynamicpb.NewMessage(reqDesc)
+        set(bad, name, v)
+        var rows []TestModel
+        _, err := pg.ApplyAndScanList(ctx, db.NewSelect().Model((*TestModel)(nil)), bad, dynamicpb.NewMessage(respDesc), &rows, ListOptions{})
+        if pe, ok := err.(*PagerError); !ok || (pe.Code != ErrCodeInvalidRequest && pe.Code != ErrCodeInvalidCursor) {
+            t.Fatalf("%s: expected a request error, got %v", name, err)
+        }
+    }
+
+    // A response without next_page_token is a server bug
+    var rows []TestModel
+    _, err := pg.ApplyAndScanList(ctx, db.NewSelect().Model((*TestModel)(nil)), dynamicpb.NewMessage(reqDesc), dynamicpb.NewMessage(reqDesc), &rows, ListOptions{})
+    if pe, ok := err.(*PagerError); !ok || pe.Code != ErrCodeInternal {
+        t.Fatalf("expected INTERNAL_ERROR, got %v", err)
+    }
+}
+
+type plainListRequest struct {
+    size  int32
+    token string
+}
+
+func (r *plainListRequest) GetPageSize() int32   { return r.size }
+func (r *plainListRequest) GetPageToken() string { return r.token }
+
+type plainListResponse struct{ next string }
+
+func (r *plainListResponse) SetNextPageToken(s string) { r.next = s }
+
+func TestApplyAndScanList_Interfaces(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 3, LogLevel: "error"})
+    newQuery := func() *bun.SelectQuery { return db.NewSelect().Model((*TestModel)(nil)) }
+
+    req, resp := &plainListRequest{size: 100}, &plainListResponse{}
+    var rows []TestModel
+    out, err := pg.ApplyAndScanList(context.Background(), newQuery(), req, resp, &rows, ListOptions{})
+    if err != nil { t.Fatal(err) }
+    // page_size is clamped to MaxLimit; default order is PK DESC
+    if !sameIDs(ids(rows), []int64{5, 4, 3}) || resp.next == "" || out.GetPageInfo().GetLimit() != 3 {
+        t.Fatalf("unexpected page %v (next %q)", ids(rows), resp.next)
+    }
+
+    req.token, rows = resp.next, nil
+    if _, err := pg.ApplyAndScanList(context.Background(), newQuery(), req, resp, &rows, ListOptions{}); err != nil { t.Fatal(err) }
+    if !sameIDs(ids(rows), []int64{2, 1}) || resp.next != "" {
+        t.Fatalf("unexpected last page %v (next %q)", ids(rows), resp.next)
+    }
+
+    _, err = pg.ApplyAndScanList(context.Background(), newQuery(), struct{}{}, resp, &rows, ListOptions{})
+    if pe, ok := err.(*PagerError); !ok || pe.Code != ErrCodeInternal {
+        t.Fatalf("expected INTERNAL_ERROR for a request without page_size, got %v", err)
+    }
+}
@@ -0,0 +1,92 @@
+package pager
+
+import (
+    "fmt"
+    "reflect"
+    "sync"
+
+    "github.com/uptrace/bun"
+    "github.com/uptrace/bun/schema"
+)
+
+var tableInfoCache sync.Map // map[*schema.Table]*ModelInfo
+
+// InferModelInfoFromDB derives ModelInfo from the table bun builds for model on db, so
+// columns, PKs and relations match what bun actually queries (implicit snake_case columns,
+// `bun:"-"`, embedded structs and custom naming included).
+func InferModelInfoFromDB(db *bun.DB, model interface{}) (*ModelInfo, error) {
+    t := reflect.TypeOf(model)
+    for t != nil && t.Kind() == reflect.Ptr { t = t.Elem() }
+    if t == nil || t.Kind() != reflect.Struct {
+        return nil, NewInvalidRequestError(fmt.Sprintf("model must be a struct, got %v", t))
+    }
+    return InferModelInfoFromTable(db.Table(t)), nil
+}
+
+// InferModelInfoFromTable converts a bun table schema into ModelInfo. Relation columns of
+// has-one/belongs-to relations are keyed "<join alias>.<column>" as in InferModelInfo, and
+// `page:"<key>"` tags declare key aliases.
+func InferModelInfoFromTable(table *schema.Table) *ModelInfo {
+    if v, ok := tableInfoCache.Load(table); ok {
+        return v.(*ModelInfo)
+    }
+
+    info := &ModelInfo{
+        TableName:          table.Name,
+        TableAlias:         table.Alias,
+        KeyToColumn:        make(map[string]string),
+        KeyAliases:         make(map[string]string),
+        FieldIndexByColumn: make(map[string][]int),
+        ColumnTypes:        make(map[string]reflect.Type),
+        NullableColumns:    make(map[string]bool),
+        Relations:          make(map[string]string),
+        RelationFieldPaths: make(map[string][]int),
+    }
+    // Fields lists PKs first; PKs keeps their declaration order
+    for _, f := range table.Fields {
+        info.KeyToColumn[f.Name] = f.Name
+        if key := f.StructField.Tag.Get("page"); key != "" && key != "-" {
+            info.KeyAliases[key] = f.Name
+            info.KeyToColumn[key] = f.Name
+        }
+        info.FieldIndexByColumn[f.Name] = f.Index
+        info.ColumnTypes[f.Name] = f.StructField.Type
+        if isNullableType(f.StructField.Type) {
+            info.NullableColumns[f.Name] = true
+        }
+    }
+    for _, f := range table.PKs {
+        info.PKColumns = append(info.PKColumns, f.Name)
+    }
+    for _, rel := range table.Relations {
+        if rel.Type != schema.HasOneRelation && rel.Type != schema.BelongsToRelation {
+            continue
+        }
+        // bun joins the relation under the relation field's SQL name
+        alias := rel.Field.Name
+        info.Relations[alias] = rel.Field.GoName
+        for _, f := range rel.JoinTable.Fields {
+            key := alias + "." + f.Name
+            info.KeyToColumn[key] = key
+            info.RelationFieldPaths[key] = append(append([]int{}, rel.Field.Index...), f.Index...)
+            info.ColumnTypes[key] = f.StructField.Type
+            info.NullableColumns[key] = true
+        }
+    }
+
+    if len(info.PKColumns) == 0 {
+        info.PKColumns = []string{"id"}
+        info.KeyToColumn["id"] = "id"
+    }
+    tableInfoCache.Store(table, info)
+    return info
+}
+
+// modelInfoFor derives ModelInfo from bun's table schema when q is bound to a DB and
+// falls back to the struct tag parser (InferModelInfo) otherwise.
+func modelInfoFor(q *bun.SelectQuery, model interface{}) (*ModelInfo, error) {
+    if db := q.DB(); db != nil {
+        return InferModelInfoFromDB(db, model)
+    }
+    return InferModelInfo(model)
+}
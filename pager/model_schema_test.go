@@ -0,0 +1,82 @@
+package pager
+
+import (
+    "context"
+    "reflect"
+    "testing"
+
+    pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+    "github.com/uptrace/bun"
+)
+
+type schemaModel struct {
+    bun.BaseModel `bun:"table:schema_items,alias:si"`
+    ID        int64  `bun:",pk,autoincrement"`
+    Title     string // implicit column "title"
+    Internal  string `bun:"-"`
+    CreatedAt int64  `page:"createdAt"`
+}
+
+func TestInferModelInfoFromDB(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    info, err := InferModelInfoFromDB(db, (*schemaModel)(nil))
+    if err != nil { t.Fatal(err) }
+    if info.TableName != "schema_items" || info.TableAlias != "si" {
+        t.Fatalf("unexpected table %q alias %q", info.TableName, info.TableAlias)
+    }
+    if !reflect.DeepEqual(info.PKColumns, []string{"id"}) {
+        t.Fatalf("unexpected PKs %v", info.PKColumns)
+    }
+    for _, key := range []string{"id", "title", "created_at", "createdAt"} {
+        if _, ok := info.KeyToColumn[key]; !ok { t.Fatalf("missing key %q in %v", key, info.KeyToColumn) }
+    }
+    if _, ok := info.KeyToColumn["internal"]; ok {
+        t.Fatal(`bun:"-" field must not be a column`)
+    }
+    // The tag parser only sees explicitly named columns
+    parsed, err := InferModelInfo(&schemaModel{})
+    if err != nil { t.Fatal(err) }
+    if _, ok := parsed.KeyToColumn["title"]; ok {
+        t.Fatal("tag parser fallback is expected to ignore implicit columns")
+    }
+
+    if _, err := InferModelInfoFromDB(db, new(int64)); err == nil {
+        t.Fatal("expected error for a non-struct model")
+    }
+}
+
+func TestInferModelInfoFromDB_MatchesTagParser(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    for _, model := range []interface{}{&TestModel{}, &joinBook{}, &embeddedPost{}, &cm{}, &nullableModel{}} {
+        fromDB, err := InferModelInfoFromDB(db, model)
+        if err != nil { t.Fatal(err) }
+        parsed, err := InferModelInfo(model)
+        if err != nil { t.Fatal(err) }
+        if !reflect.DeepEqual(fromDB, parsed) {
+            t.Fatalf("%T: schema %+v != tags %+v", model, fromDB, parsed)
+        }
+    }
+}
+
+func TestApplyAndScan_ImplicitColumnFromSchema(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    if _, err := db.NewCreateTable().Model((*schemaModel)(nil)).Exec(ctx); err != nil {
+        t.Fatal(err)
+    }
+    items := []schemaModel{{Title: "c", CreatedAt: 1}, {Title: "a", CreatedAt: 2}, {Title: "b", CreatedAt: 3}}
+    if _, err := db.NewInsert().Model(&items).Exec(ctx); err != nil {
+        t.Fatal(err)
+    }
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error"})
+    rows := walkPages[schemaModel](t, pg, func() *bun.SelectQuery { return db.NewSelect().Model((*schemaModel)(nil)) },
+        []*pagerpb.Order{{Key: "title", Asc: true}}, 2, false)
+    var got []string
+    for _, r := range rows { got = append(got, r.Title) }
+    if !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+        t.Fatalf("unexpected walk %v", got)
+    }
+}
@@ -0,0 +1,170 @@
+package pager
+
+import (
+    "context"
+    "database/sql"
+    "fmt"
+    "testing"
+
+    pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+    "github.com/uptrace/bun"
+    "github.com/uptrace/bun/dialect"
+)
+
+type nullableModel struct {
+    ID          int64         `bun:"id,pk,autoincrement"`
+    PublishedAt *int64        `bun:"published_at"`
+    Rank        sql.NullInt64 `bun:"rank"`
+}
+
+func setupNullableDB(t *testing.T) *bun.DB {
+    db := setupTestDB(t)
+    ctx := context.Background()
+    if _, err := db.NewCreateTable().Model((*nullableModel)(nil)).Exec(ctx); err != nil {
+        t.Fatal(err)
+    }
+    p := func(v int64) *int64 { return &v }
+    rows := []nullableModel{
+        {PublishedAt: p(30)}, {PublishedAt: nil}, {PublishedAt: p(10)}, {PublishedAt: nil},
+        {PublishedAt: p(30)}, {PublishedAt: p(20)}, {PublishedAt: nil}, {PublishedAt: p(10)},
+    }
+    for i := range rows {
+        if i%2 == 0 { rows[i].Rank = sql.NullInt64{Int64: int64(i % 3), Valid: true} }
+    }
+    if _, err := db.NewInsert().Model(&rows).Exec(ctx); err != nil {
+        t.Fatal(err)
+    }
+    return db
+}
+
+func nullableIDs(rows []nullableModel) []int64 {
+    out := make([]int64, len(rows))
+    for i, r := range rows { out[i] = r.ID }
+    return out
+}
+
+func TestInferModelInfo_NullableColumns(t *testing.T) {
+    info, err := InferModelInfo(&nullableModel{})
+    if err != nil { t.Fatal(err) }
+    if !info.NullableColumns["published_at"] || !info.NullableColumns["rank"] || info.NullableColumns["id"] {
+        t.Fatalf("unexpected nullable columns %v", info.NullableColumns)
+    }
+}
+
+func TestBuildOrderPlan_NullsPlacement(t *testing.T) {
+    info, err := InferModelInfo(&nullableModel{})
+    if err != nil { t.Fatal(err) }
+    plan, err := BuildOrderPlan([]OrderSpecInterface{
+        &pagerpb.Order{Key: "published_at", Asc: true},
+        &pagerpb.Order{Key: "rank", Asc: true, Nulls: pagerpb.Nulls_NULLS_FIRST},
+    }, info, nil)
+    if err != nil { t.Fatal(err) }
+    if plan.Items[0].Nulls != NullsLast || plan.Items[1].Nulls != NullsFirst || plan.Items[2].Nulls != "" {
+        t.Fatalf("unexpected nulls placement %+v", plan.Items)
+    }
+    rev := plan.Reversed()
+    if rev.Items[0].Nulls != NullsFirst || rev.Items[1].Nulls != NullsLast {
+        t.Fatalf("reversing must flip nulls placement: %+v", rev.Items)
+    }
+}
+
+func TestBuildCursorWhere_Nulls(t *testing.T) {
+    plan := &OrderPlan{Items: []OrderItem{
+        {Column: "published_at", Direction: "ASC", Nulls: NullsLast},
+        {Column: "id", Direction: "ASC"},
+    }}
+
+    // Non-NULL anchor, NULLs last: NULL rows still follow
+    where, args, err := BuildCursorWhere(&CursorData{Values: map[string]interface{}{"published_at": int64(10), "id": int64(3)}}, plan)
+    if err != nil { t.Fatal(err) }
+    want := `(((("test_model"."published_at" > 10 OR "test_model"."published_at" IS NULL)) OR ("test_model"."published_at" = 10 AND "test_model"."id" > 3)))`
+    if got := renderWhere(t, where, args); got != want {
+        t.Fatalf("unexpected where %s", got)
+    }
+
+    // NULL anchor, NULLs last: only the NULL group tail follows
+    where, args, err = BuildCursorWhere(&CursorData{Values: map[string]interface{}{"published_at": (*int64)(nil), "id": int64(3)}}, plan)
+    if err != nil { t.Fatal(err) }
+    if got := renderWhere(t, where, args); got != `((("test_model"."published_at" IS NULL AND "test_model"."id" > 3)))` {
+        t.Fatalf("unexpected where %s", got)
+    }
+
+    // NULL anchor, NULLs first: every non-NULL row follows
+    plan.Items[0].Nulls = NullsFirst
+    where, args, err = BuildCursorWhere(&CursorData{Values: map[string]interface{}{"published_at": nil, "id": int64(3)}}, plan)
+    if err != nil { t.Fatal(err) }
+    want = `((("test_model"."published_at" IS NOT NULL) OR ("test_model"."published_at" IS NULL AND "test_model"."id" > 3)))`
+    if got := renderWhere(t, where, args); got != want {
+        t.Fatalf("unexpected where %s", got)
+    }
+}
+
+func TestNullsOrderKey_Dialects(t *testing.T) {
+    for _, tc := range []struct {
+        name  dialect.Name
+        nulls string
+        want  string
+    }{
+        {dialect.MySQL, NullsLast, "?TableAlias.? IS NULL ASC"},
+        {dialect.SQLite, NullsFirst, "?TableAlias.? IS NULL DESC"},
+        // T-SQL has no boolean expressions in ORDER BY
+        {dialect.MSSQL, NullsLast, "CASE WHEN ?TableAlias.? IS NULL THEN 1 ELSE 0 END ASC"},
+        {dialect.MSSQL, NullsFirst, "CASE WHEN ?TableAlias.? IS NULL THEN 1 ELSE 0 END DESC"},
+    } {
+        if got := nullsOrderKey(tc.name, "?TableAlias.?", tc.nulls); got != tc.want {
+            t.Fatalf("%v %s: expected %q, got %q", tc.name, tc.nulls, tc.want, got)
+        }
+    }
+}
+
+func TestApplyAndScan_NullableOrderFullScan(t *testing.T) {
+    for _, key := range []string{"published_at", "rank"} {
+        for _, asc := range []bool{true, false} {
+            for _, nulls := range []pagerpb.Nulls{pagerpb.Nulls_NULLS_UNSPECIFIED, pagerpb.Nulls_NULLS_FIRST, pagerpb.Nulls_NULLS_LAST} {
+                for _, selfContained := range []bool{false, true} {
+                    name := fmt.Sprintf("%s/asc=%v/%s/self=%v", key, asc, nulls, selfContained)
+                    t.Run(name, func(t *testing.T) {
+                        testNullableFullScan(t, &pagerpb.Order{Key: key, Asc: asc, Nulls: nulls}, selfContained)
+                    })
+                }
+            }
+        }
+    }
+}
+
+func testNullableFullScan(t *testing.T, order *pagerpb.Order, selfContained bool) {
+    db := setupNullableDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    pg := New(&Options{DefaultLimit: 3, MaxLimit: 100, LogLevel: "error", SelfContainedCursor: selfContained})
+    orders := []*pagerpb.Order{order}
+
+    var all []nullableModel
+    if _, err := pg.ApplyAndScan(ctx, db.NewSelect().Model((*nullableModel)(nil)), &pagerpb.Page{Limit: 100, Order: orders}, &all); err != nil {
+        t.Fatal(err)
+    }
+    if len(all) != 8 { t.Fatalf("expected 8 rows, got %d", len(all)) }
+
+    // NULLs grouped at the requested end
+    isNull := func(r nullableModel) bool {
+        if order.Key == "rank" { return !r.Rank.Valid }
+        return r.PublishedAt == nil
+    }
+    nullsFirst := order.Nulls == pagerpb.Nulls_NULLS_FIRST || (order.Nulls == pagerpb.Nulls_NULLS_UNSPECIFIED && !order.Asc)
+    edge := all[0]
+    if !nullsFirst { edge = all[len(all)-1] }
+    if !isNull(edge) { t.Fatalf("expected NULL rows first=%v, got %v", nullsFirst, nullableIDs(all)) }
+
+    // Forward walk visits every row exactly once, in the same order
+    newQuery := func() *bun.SelectQuery { return db.NewSelect().Model((*nullableModel)(nil)) }
+    forward := walkPages[nullableModel](t, pg, newQuery, orders, 3, false)
+    if !sameIDs(nullableIDs(forward), nullableIDs(all)) {
+        t.Fatalf("forward walk %v, want %v", nullableIDs(forward), nullableIDs(all))
+    }
+
+    // Backward walk from the end yields the same sequence
+    backward := walkPages[nullableModel](t, pg, newQuery, orders, 3, true)
+    if !sameIDs(nullableIDs(backward), nullableIDs(all)) {
+        t.Fatalf("backward walk %v, want %v", nullableIDs(backward), nullableIDs(all))
+    }
+}
@@ -0,0 +1,103 @@
+package pager
+
+import (
+    "fmt"
+    "strings"
+
+    pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+)
+
+// ParseOrderBy parses an AIP-132 order_by string ("created_at desc, name") into the
+// order specs BuildOrderPlan takes: comma-separated keys, ascending unless followed by
+// "desc" ("asc" may be spelled out). A blank string yields no specs. Empty items, unknown
+// directions and repeated keys are INVALID_REQUEST naming the byte offset.
+func ParseOrderBy(orderBy string) ([]OrderSpecInterface, error) {
+    var specs []OrderSpecInterface
+    if strings.TrimSpace(orderBy) == "" {
+        return nil, nil
+    }
+    seen := map[string]bool{}
+    start := 0
+    for _, item := range strings.Split(orderBy, ",") {
+        words, offsets := orderByWords(item, start)
+        switch {
+        case len(words) == 0:
+            return nil, newPositionError("order_by: empty order item", start)
+        case !isFieldPath(words[0]):
+            return nil, newPositionError(fmt.Sprintf("order_by: invalid key %q", words[0]), offsets[0])
+        case seen[words[0]]:
+            return nil, newPositionError(fmt.Sprintf("order_by: duplicate key %q", words[0]), offsets[0])
+        case len(words) > 2:
+            return nil, newPositionError(fmt.Sprintf("order_by: unexpected %q after %q", words[2], words[0]+" "+words[1]), offsets[2])
+        }
+        seen[words[0]] = true
+        order := &pagerpb.Order{Key: words[0], Asc: true}
+        if len(words) == 2 {
+            switch strings.ToLower(words[1]) {
+            case "asc":
+            case "desc":
+                order.Asc = false
+            default:
+                return nil, newPositionError(fmt.Sprintf("order_by: unknown direction %q (want asc or desc)", words[1]), offsets[1])
+            }
+        }
+        specs = append(specs, order)
+        start += len(item) + 1
+    }
+    return specs, nil
+}
+
+// orderByWords splits an order_by item into words with their offsets in the string.
+func orderByWords(item string, start int) ([]string, []int) {
+    var words []string
+    var offsets []int
+    for i := 0; i < len(item); {
+        if item[i] == ' ' || item[i] == '\t' {
+            i++
+            continue
+        }
+        j := i
+        for j < len(item) && item[j] != ' ' && item[j] != '\t' {
+            j++
+        }
+        words, offsets = append(words, item[i:j]), append(offsets, start+i)
+        i = j
+    }
+    return words, offsets
+}
+
+// requestOrders returns the order specs of a request: order, or order_by parsed as
+// AIP-132. Setting both is INVALID_REQUEST.
+func requestOrders(in *pagerpb.Page) ([]OrderSpecInterface, error) {
+    if strings.TrimSpace(in.GetOrderBy()) != "" {
+        if len(in.GetOrder()) > 0 {
+            return nil, NewInvalidRequestError("cannot specify both order and order_by")
+        }
+        return ParseOrderBy(in.GetOrderBy())
+    }
+    orders := make([]OrderSpecInterface, 0, len(in.GetOrder()))
+    for _, o := range in.GetOrder() {
+        if o == nil { continue }
+        orders = append(orders, o)
+    }
+    return orders, nil
+}
+
+// checkOrderByColumns rejects order_by keys that resolve to the same column (an alias and
+// its raw column), which buildOrderPlan would otherwise silently collapse into one.
+// Unresolvable keys are left to buildOrderPlan to report.
+func checkOrderByColumns(orders []OrderSpecInterface, modelInfo *ModelInfo, opts *Options) error {
+    seen := map[string]string{}
+    for _, order := range orders {
+        key := order.GetKey()
+        column, _, err := resolveOrderKey(key, modelInfo, opts)
+        if err != nil {
+            continue
+        }
+        if prev, ok := seen[column]; ok {
+            return NewInvalidRequestError(fmt.Sprintf("order_by: duplicate key %q (same field as %q)", key, prev))
+        }
+        seen[column] = key
+    }
+    return nil
+}
@@ -0,0 +1,71 @@
+package pager
+
+import (
+    "context"
+    "testing"
+
+    pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+)
+
+func TestParseOrderBy(t *testing.T) {
+    specs, err := ParseOrderBy(" created_at desc,name , author.name ASC")
+    if err != nil { t.Fatal(err) }
+    want := []*pagerpb.Order{{Key: "created_at"}, {Key: "name", Asc: true}, {Key: "author.name", Asc: true}}
+    if len(specs) != len(want) {
+        t.Fatalf("expected %d specs, got %v", len(want), specs)
+    }
+    for i, s := range specs {
+        if s.GetKey() != want[i].Key || s.GetAsc() != want[i].Asc {
+            t.Fatalf("spec %d: expected %v, got %v", i, want[i], s)
+        }
+    }
+    if specs, err := ParseOrderBy("  "); specs != nil || err != nil {
+        t.Fatalf("blank order_by must yield no specs, got %v %v", specs, err)
+    }
+
+    for _, tc := range []struct {
+        orderBy string
+        pos     int
+    }{
+        {"name,,score", 5},
+        {"name,", 5},
+        {"name up", 5},
+        {"name, score, name desc", 13},
+        {"name desc first", 10},
+        {"lower(name)", 0},
+    } {
+        _, err := ParseOrderBy(tc.orderBy)
+        expectFilterPosition(t, err, tc.pos)
+    }
+}
+
+func TestApplyAndScan_OrderBy(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error"})
+
+    var rows []TestModel
+    out, err := pg.ApplyAndScan(ctx, db.NewSelect().Model((*TestModel)(nil)), &pagerpb.Page{OrderBy: "score desc"}, &rows)
+    if err != nil { t.Fatal(err) }
+    if !sameIDs(ids(rows), []int64{3, 1}) || out.OrderBy != "score desc" {
+        t.Fatalf("unexpected first page %v (%q)", ids(rows), out.OrderBy)
+    }
+
+    // order_by and the equivalent repeated order share cursors
+    rows = nil
+    in := &pagerpb.Page{Order: []*pagerpb.Order{{Key: "score"}}, Selector: &pagerpb.Page_Cursor{Cursor: out.GetCursor()}}
+    if _, err := pg.ApplyAndScan(ctx, db.NewSelect().Model((*TestModel)(nil)), in, &rows); err != nil { t.Fatal(err) }
+    if !sameIDs(ids(rows), []int64{5, 2}) {
+        t.Fatalf("unexpected second page %v", ids(rows))
+    }
+
+    for _, in := range []*pagerpb.Page{
+        {OrderBy: "score", Order: []*pagerpb.Order{{Key: "score"}}},
+        {OrderBy: "score sideways"},
+        {OrderBy: "nope"},
+    } {
+        _, err := pg.ApplyAndScan(ctx, db.NewSelect().Model((*TestModel)(nil)), in, &rows)
+        expectInvalidRequest(t, err)
+    }
+}
@@ -0,0 +1,51 @@
+package pager
+
+import (
+    "fmt"
+    "reflect"
+)
+
+// OrderExpr is a server-defined SQL expression exposed as a logical order key
+// (e.g. "name_ci" -> lower(name)), registered in Options.OrderExprs.
+//
+// SQL is trusted and spliced into ORDER BY and cursor predicates as is; reference model
+// columns as ?TableAlias.column so they stay qualified on joined queries. The expression
+// must be deterministic and evaluate to the same value as Extract, otherwise cursor
+// predicates skip or repeat rows.
+type OrderExpr struct {
+    // SQL is the expression, e.g. "lower(?TableAlias.name)" or
+    // "coalesce(?TableAlias.published_at, ?TableAlias.created_at)".
+    SQL string
+    // Args bind the placeholders of SQL, if any.
+    Args []interface{}
+    // Extract returns the expression value for a scanned row, always a pointer to the model
+    // struct (e.g. row.(*User)). Expression keys therefore require the model as destination:
+    // DTO, map and scalar destinations are rejected with INVALID_REQUEST.
+    Extract func(row interface{}) interface{}
+    // Nullable marks expressions that can evaluate to NULL; their order items get a
+    // NULL placement like nullable columns.
+    Nullable bool
+}
+
+// validate reports a misconfigured registry entry.
+func (e *OrderExpr) validate(key string) error {
+    if e.SQL == "" || e.Extract == nil {
+        return fmt.Errorf("order expression %q requires SQL and Extract", key)
+    }
+    return nil
+}
+
+// ref returns the parenthesized expression and its args.
+func (e *OrderExpr) ref() (string, []interface{}) {
+    return "(" + e.SQL + ")", e.Args
+}
+
+// value runs Extract on a struct row value, passing a pointer to it.
+func (e *OrderExpr) value(v reflect.Value) interface{} {
+    if v.CanAddr() {
+        return e.Extract(v.Addr().Interface())
+    }
+    ptr := reflect.New(v.Type())
+    ptr.Elem().Set(v)
+    return e.Extract(ptr.Interface())
+}
@@ -0,0 +1,162 @@
+package pager
+
+import (
+    "context"
+    "fmt"
+    "strings"
+    "testing"
+
+    pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+    "github.com/uptrace/bun"
+)
+
+var testOrderExprs = map[string]OrderExpr{
+    "name_ci": {
+        SQL:     "lower(?TableAlias.name)",
+        Extract: func(row interface{}) interface{} { return strings.ToLower(row.(*TestModel).Name) },
+    },
+    // Mostly ties (90, 85, 95, 80 -> 0): the PK tiebreaker decides within them
+    "score_mod": {
+        SQL:     "?TableAlias.score % ?",
+        Args:    []interface{}{5},
+        Extract: func(row interface{}) interface{} { return int64(row.(*TestModel).Score % 5) },
+    },
+}
+
+func TestBuildOrderPlan_OrderExpr(t *testing.T) {
+    info, err := InferModelInfo(&TestModel{})
+    if err != nil { t.Fatal(err) }
+    specs := []OrderSpecInterface{&pagerpb.Order{Key: "name_ci", Asc: true}}
+    plan, err := buildOrderPlan(specs, info, &Options{OrderExprs: testOrderExprs})
+    if err != nil { t.Fatal(err) }
+    if len(plan.Items) != 2 || plan.Items[0].Expr == nil || plan.Items[0].Column != "name_ci" || plan.Items[0].Nulls != "" {
+        t.Fatalf("unexpected plan %+v", plan.Items)
+    }
+
+    // Without the registry the key is unknown
+    if _, err := BuildOrderPlan(specs, info, nil); err == nil {
+        t.Fatal("expected unsupported order key without the registry")
+    }
+    // AllowedOrderKeys applies to expression keys too
+    if _, err := buildOrderPlan(specs, info, &Options{AllowedOrderKeys: []string{"name"}, OrderExprs: testOrderExprs}); err == nil {
+        t.Fatal("expected expression key rejected by AllowedOrderKeys")
+    }
+    // Registry entries need SQL and Extract
+    if _, err := buildOrderPlan(specs, info, &Options{OrderExprs: map[string]OrderExpr{"name_ci": {SQL: "lower(name)"}}}); err == nil {
+        t.Fatal("expected error for an expression without Extract")
+    }
+
+    // The expression SQL is part of the fingerprint
+    other, err := buildOrderPlan(specs, info, &Options{OrderExprs: map[string]OrderExpr{"name_ci": {SQL: "upper(?TableAlias.name)", Extract: testOrderExprs["name_ci"].Extract}}})
+    if err != nil { t.Fatal(err) }
+    if other.Fingerprint() == plan.Fingerprint() {
+        t.Fatal("expected different fingerprints for different expressions")
+    }
+}
+
+func TestBuildCursorWhere_OrderExpr(t *testing.T) {
+    expr := testOrderExprs["score_mod"]
+    plan := &OrderPlan{Items: []OrderItem{
+        {Column: "score_mod", Direction: "DESC", Expr: &expr},
+        {Column: "id", Direction: "DESC"},
+    }}
+    cd := &CursorData{Values: map[string]interface{}{"score_mod": int64(4), "id": int64(3)}}
+    where, args, err := BuildCursorWhere(cd, plan)
+    if err != nil { t.Fatal(err) }
+    want := `((("test_model".score % 5) < 4) OR ` +
+        `(("test_model".score % 5) = 4 AND "test_model"."id" < 3)))`
+    if got := renderWhere(t, where, args); got != "("+want {
+        t.Fatalf("unexpected where %s", got)
+    }
+    where, args, ok := BuildTupleCursorWhere(cd, plan)
+    want = `((("test_model".score % 5), "test_model"."id") < (4, 3))`
+    if got := renderWhere(t, where, args); !ok || got != want {
+        t.Fatalf("unexpected tuple where %s (ok=%v)", got, ok)
+    }
+}
+
+func TestApplyAndScan_OrderExprWalk(t *testing.T) {
+    for _, key := range []string{"name_ci", "score_mod"} {
+        for _, asc := range []bool{true, false} {
+            for _, selfContained := range []bool{false, true} {
+                for _, strategy := range []CursorWhereStrategy{CursorWhereOrChain, CursorWhereTuple, CursorWhereFactored} {
+                    t.Run(fmt.Sprintf("%s/asc=%v/self=%v/%d", key, asc, selfContained, strategy), func(t *testing.T) {
+                        db := setupTestDB(t)
+                        defer db.Close()
+                        pg := New(&Options{
+                            DefaultLimit: 2, MaxLimit: 10, LogLevel: "error", OrderExprs: testOrderExprs,
+                            SelfContainedCursor: selfContained, CursorWhere: strategy,
+                        })
+                        order := []*pagerpb.Order{{Key: key, Asc: asc}}
+                        newQuery := func() *bun.SelectQuery { return db.NewSelect().Model((*TestModel)(nil)) }
+
+                        var all []TestModel
+                        if _, err := pg.ApplyAndScan(context.Background(), newQuery(), &pagerpb.Page{Limit: 10, Order: order}, &all); err != nil {
+                            t.Fatal(err)
+                        }
+                        if len(all) != 5 { t.Fatalf("expected 5 rows, got %d", len(all)) }
+                        forward := walkPages[TestModel](t, pg, newQuery, order, 2, false)
+                        if !sameIDs(ids(forward), ids(all)) {
+                            t.Fatalf("forward walk %v, want %v", ids(forward), ids(all))
+                        }
+                        backward := walkPages[TestModel](t, pg, newQuery, order, 2, true)
+                        if !sameIDs(ids(backward), ids(all)) {
+                            t.Fatalf("backward walk %v, want %v", ids(backward), ids(all))
+                        }
+                    })
+                }
+            }
+        }
+    }
+}
+
+func TestApplyAndScan_OrderExprRequiresModelDestination(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error", OrderExprs: testOrderExprs})
+    newQuery := func() *bun.SelectQuery { return db.NewSelect().Model((*TestModel)(nil)) }
+
+    // Extract reads *TestModel: projections would hand it a DTO, map or scalar
+    for _, in := range []*pagerpb.Page{
+        {Order: []*pagerpb.Order{{Key: "name_ci", Asc: true}}},
+        {Order: []*pagerpb.Order{{Key: "name_ci", Asc: true}}, Selector: &pagerpb.Page_Page{Page: 1}},
+    } {
+        var dtos []nameDTO
+        _, err := pg.ApplyAndScan(context.Background(), newQuery().Column("id", "name"), in, &dtos)
+        expectInvalidRequest(t, err)
+        var maps []map[string]interface{}
+        _, err = pg.ApplyAndScan(context.Background(), newQuery(), in, &maps)
+        expectInvalidRequest(t, err)
+        var scalars []int64
+        _, err = pg.ApplyAndScan(context.Background(), newQuery().Column("id"), in, &scalars)
+        expectInvalidRequest(t, err)
+    }
+}
+
+func TestApplyAndScan_NullableOrderExpr(t *testing.T) {
+    db := setupNullableDB(t)
+    defer db.Close()
+    exprs := map[string]OrderExpr{"effective": {
+        SQL: "coalesce(?TableAlias.published_at, ?TableAlias.rank)",
+        Extract: func(row interface{}) interface{} {
+            m := row.(*nullableModel)
+            if m.PublishedAt != nil { return *m.PublishedAt }
+            if m.Rank.Valid { return m.Rank.Int64 }
+            return nil
+        },
+        Nullable: true,
+    }}
+    for _, asc := range []bool{true, false} {
+        pg := New(&Options{DefaultLimit: 3, MaxLimit: 100, LogLevel: "error", OrderExprs: exprs})
+        order := []*pagerpb.Order{{Key: "effective", Asc: asc}}
+        newQuery := func() *bun.SelectQuery { return db.NewSelect().Model((*nullableModel)(nil)) }
+        var all []nullableModel
+        if _, err := pg.ApplyAndScan(context.Background(), newQuery(), &pagerpb.Page{Limit: 100, Order: order}, &all); err != nil {
+            t.Fatal(err)
+        }
+        forward := walkPages[nullableModel](t, pg, newQuery, order, 3, false)
+        if len(all) != 8 || !sameIDs(nullableIDs(forward), nullableIDs(all)) {
+            t.Fatalf("asc=%v: forward walk %v, want %v", asc, nullableIDs(forward), nullableIDs(all))
+        }
+    }
+}
@@ -0,0 +1,59 @@
+package pager
+
+import pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+
+// PageInfo describes a returned page, independent of the transport.
+//   - HasNext/HasPrevious: another page exists in that direction. Offset mode: HasPrevious is page > 1.
+//     Cursor mode: relative to the request cursor.
+//   - StartCursor/EndCursor: cursors of the first/last returned row ("" when no rows),
+//     set in offset mode too so a client can switch to cursor paging.
+//   - Count: rows returned. Limit: effective limit after defaulting/clamping.
+//   - TotalCount/TotalPages: only set when the request asked for totals (include_total).
+//     TotalCapped: the real total exceeds TotalCount. TotalEstimated: TotalCount is a planner estimate.
+type PageInfo struct {
+    HasNext        bool
+    HasPrevious    bool
+    StartCursor    string
+    EndCursor      string
+    Count          int
+    Limit          int
+    TotalCount     int64
+    TotalPages     int
+    TotalCapped    bool
+    TotalEstimated bool
+}
+
+// Proto converts the info into its wire message.
+func (i *PageInfo) Proto() *pagerpb.PageInfo {
+    if i == nil {
+        return nil
+    }
+    return &pagerpb.PageInfo{
+        HasNext:        i.HasNext,
+        HasPrevious:    i.HasPrevious,
+        StartCursor:    i.StartCursor,
+        EndCursor:      i.EndCursor,
+        Count:          uint32(i.Count),
+        Limit:          uint32(i.Limit),
+        TotalCount:     uint64(i.TotalCount),
+        TotalPages:     uint32(i.TotalPages),
+        TotalCapped:    i.TotalCapped,
+        TotalEstimated: i.TotalEstimated,
+    }
+}
+
+// PageInfoFromProto converts a wire PageInfo back into the Go type. Nil yields the zero value.
+func PageInfoFromProto(m *pagerpb.PageInfo) PageInfo {
+    return PageInfo{
+        HasNext:        m.GetHasNext(),
+        HasPrevious:    m.GetHasPrevious(),
+        StartCursor:    m.GetStartCursor(),
+        EndCursor:      m.GetEndCursor(),
+        Count:          int(m.GetCount()),
+        Limit:          int(m.GetLimit()),
+        TotalCount:     int64(m.GetTotalCount()),
+        TotalPages:     int(m.GetTotalPages()),
+        TotalCapped:    m.GetTotalCapped(),
+        TotalEstimated: m.GetTotalEstimated(),
+    }
+}
@@ -0,0 +1,81 @@
+package pager
+
+import (
+    "context"
+    "testing"
+
+    pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+)
+
+func TestPageInfo_OffsetMode(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error"})
+
+    cases := []struct {
+        page             uint32
+        count            int
+        hasNext, hasPrev bool
+    }{
+        {page: 1, count: 2, hasNext: true, hasPrev: false},
+        {page: 2, count: 2, hasNext: true, hasPrev: true},
+        {page: 3, count: 1, hasNext: false, hasPrev: true},
+        {page: 4, count: 0, hasNext: false, hasPrev: true},
+    }
+    for _, tc := range cases {
+        var rows []TestModel
+        out, err := pg.ApplyAndScan(ctx, db.NewSelect().Model(&TestModel{}), &pagerpb.Page{Limit: 2, Selector: &pagerpb.Page_Page{Page: tc.page}}, &rows)
+        if err != nil { t.Fatal(err) }
+        info := PageInfoFromProto(out.PageInfo)
+        if info.Count != tc.count || info.Limit != 2 || info.HasNext != tc.hasNext || info.HasPrevious != tc.hasPrev {
+            t.Fatalf("page %d: unexpected info %+v", tc.page, info)
+        }
+        if (tc.count > 0) != (info.StartCursor != "" && info.EndCursor != "") {
+            t.Fatalf("page %d: start/end cursors must be set exactly when rows are returned: %+v", tc.page, info)
+        }
+    }
+}
+
+func TestPageInfo_CursorMode(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    pg := New(&Options{DefaultLimit: 3, MaxLimit: 10, LogLevel: "error"})
+    order := []*pagerpb.Order{{Key: "created_at", Asc: true}}
+
+    var first []TestModel
+    out1, err := pg.ApplyAndScan(ctx, db.NewSelect().Model(&TestModel{}), &pagerpb.Page{Limit: 3, Order: order}, &first)
+    if err != nil { t.Fatal(err) }
+    info1 := PageInfoFromProto(out1.PageInfo)
+    if !info1.HasNext || info1.HasPrevious || info1.Count != 3 || info1.Limit != 3 {
+        t.Fatalf("unexpected first page info %+v", info1)
+    }
+    if info1.EndCursor != out1.GetCursor() {
+        t.Fatal("next cursor must equal the end cursor when a next page exists")
+    }
+
+    var second []TestModel
+    out2, err := pg.ApplyAndScan(ctx, db.NewSelect().Model(&TestModel{}), &pagerpb.Page{Limit: 3, Order: order, Selector: &pagerpb.Page_Cursor{Cursor: info1.EndCursor}}, &second)
+    if err != nil { t.Fatal(err) }
+    info2 := PageInfoFromProto(out2.PageInfo)
+    if info2.HasNext || !info2.HasPrevious || info2.Count != 2 {
+        t.Fatalf("unexpected last page info %+v", info2)
+    }
+    if out2.GetCursor() != "" || info2.EndCursor == "" {
+        t.Fatal("last page: no next cursor, but the end cursor is still reported")
+    }
+    if out2.PrevCursor != info2.StartCursor {
+        t.Fatal("previous cursor must equal the start cursor when a previous page exists")
+    }
+}
+
+func TestPageInfo_ProtoRoundTrip(t *testing.T) {
+    in := PageInfo{HasNext: true, HasPrevious: true, StartCursor: "a", EndCursor: "b", Count: 3, Limit: 20}
+    if got := PageInfoFromProto(in.Proto()); got != in {
+        t.Fatalf("round trip mismatch: %+v != %+v", got, in)
+    }
+    if got := PageInfoFromProto(nil); got != (PageInfo{}) {
+        t.Fatalf("nil proto must yield zero value, got %+v", got)
+    }
+}
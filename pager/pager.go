@@ -5,14 +5,39 @@ type Options struct {
     MaxLimit     int
     LogLevel     string
     AllowedOrderKeys []string
+    // OrderExprs registers logical order keys backed by SQL expressions (see OrderExpr).
+    // Their keys are subject to AllowedOrderKeys like column keys.
+    OrderExprs map[string]OrderExpr
+    // AllowedFilterKeys restricts the fields a request Filter may reference (empty: any
+    // model column or key alias).
+    AllowedFilterKeys []string
+    // KeyAliases maps logical keys exposed to clients to model columns
+    // ("createdAt" -> "created_at"), in addition to `page:"..."` struct tags.
+    KeyAliases map[string]string
+    // StrictKeys rejects raw column names: only aliased keys (KeyAliases or `page` tags)
+    // and OrderExprs keys are accepted, for order and filter keys alike.
+    StrictKeys bool
     DefaultOrderSpecs []OrderSpecInterface
+    // SelfContainedCursor makes next cursors carry every order column value
+    // instead of the PK only, which skips the anchor fetch on the following page.
+    SelfContainedCursor bool
+    // CursorSigner, when set, signs every cursor token and rejects unsigned or tampered ones.
+    CursorSigner *CursorSigner
+    // CursorCodec encodes cursor payloads into tokens (default: URL-safe base64).
+    // Use NewAESGCMCursorCodec to hide PK values from clients.
+    CursorCodec CursorCodec
+    // CursorWhere selects the cursor predicate rendering (default: OR-chain).
+    CursorWhere CursorWhereStrategy
+    // TotalCountCap bounds TOTAL_MODE_CAPPED counts (default 1000).
+    TotalCountCap int
 }
 
 func DefaultOptions() *Options {
 	return &Options{
-		DefaultLimit: 20,
-		MaxLimit:     100,
-		LogLevel:     "warn",
+		DefaultLimit:  20,
+		MaxLimit:      100,
+		LogLevel:      "warn",
+		TotalCountCap: 1000,
 	}
 }
 
@@ -32,6 +57,9 @@ func New(opts *Options) *Pager {
     if opts.MaxLimit <= 0 {
         opts.MaxLimit = DefaultOptions().MaxLimit
     }
+    if opts.TotalCountCap <= 0 {
+        opts.TotalCountCap = DefaultOptions().TotalCountCap
+    }
     if opts.LogLevel == "" {
         opts.LogLevel = DefaultOptions().LogLevel
     }
@@ -18,8 +18,6 @@ type TestModel struct {
 	Score     int    `bun:"score"`
 }
 
-// Composite PK types are intentionally unsupported by the library.
-
 func setupTestDB(t *testing.T) *bun.DB {
 	sqlDB, err := sql.Open(sqliteshim.ShimName, ":memory:")
 	if err != nil {
@@ -197,5 +195,3 @@ func TestAllowedOrderKeysEnforced(t *testing.T) {
         t.Fatal("expected error for unsupported order key")
     }
 }
-
-// Composite PK is not supported: library assumes a single PK (default: id)
@@ -0,0 +1,48 @@
+package pager
+
+import (
+    "context"
+
+    pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+    "github.com/uptrace/bun"
+)
+
+// Result is a typed page returned by Paginate.
+type Result[T any] struct {
+    // Items are the rows of the page, in request order.
+    Items []T
+    // NextCursor fetches the following page ("" when there is none).
+    NextCursor string
+    // PrevCursor fetches the preceding page with DIRECTION_BEFORE ("" on the first page).
+    PrevCursor string
+    // HasMore reports another page in the requested direction: HasPrevious for
+    // DIRECTION_BEFORE, HasNext otherwise.
+    HasMore  bool
+    PageInfo PageInfo
+    // Page is the response message ApplyAndScan returns, for handlers that echo it.
+    Page *pagerpb.Page
+}
+
+// Paginate is the typed form of ApplyAndScan: rows are scanned into []T, so a wrong
+// destination is a compile error rather than INVALID_REQUEST. T is the model struct
+// (or a pointer to it), a projection DTO, map[string]interface{} or a scalar, as for ApplyAndScan.
+func Paginate[T any](ctx context.Context, p *Pager, q *bun.SelectQuery, in *pagerpb.Page) (*Result[T], error) {
+    var items []T
+    out, err := p.ApplyAndScan(ctx, q, in, &items)
+    if err != nil {
+        return nil, err
+    }
+    info := PageInfoFromProto(out.PageInfo)
+    res := &Result[T]{
+        Items:      items,
+        NextCursor: out.GetCursor(),
+        PrevCursor: out.PrevCursor,
+        HasMore:    info.HasNext,
+        PageInfo:   info,
+        Page:       out,
+    }
+    if in.GetDirection() == pagerpb.Direction_DIRECTION_BEFORE {
+        res.HasMore = info.HasPrevious
+    }
+    return res, nil
+}
@@ -0,0 +1,58 @@
+package pager
+
+import (
+    "context"
+    "testing"
+
+    pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+)
+
+func TestPaginate_ForwardAndBackward(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error"})
+    order := []*pagerpb.Order{{Key: "created_at", Asc: true}}
+
+    var got []int64
+    cursor := ""
+    for i := 0; i < 10; i++ {
+        res, err := Paginate[TestModel](ctx, pg, db.NewSelect().Model((*TestModel)(nil)), &pagerpb.Page{
+            Limit: 2, Order: order, Selector: &pagerpb.Page_Cursor{Cursor: cursor},
+        })
+        if err != nil { t.Fatal(err) }
+        got = append(got, ids(res.Items)...)
+        if res.HasMore != (res.NextCursor != "") || res.PageInfo.Count != len(res.Items) {
+            t.Fatalf("inconsistent result %+v", res)
+        }
+        if cursor = res.NextCursor; !res.HasMore { break }
+    }
+    if !sameIDs(got, []int64{1, 2, 3, 4, 5}) {
+        t.Fatalf("unexpected walk %v", got)
+    }
+
+    // Backwards from the end: HasMore follows the requested direction
+    res, err := Paginate[*TestModel](ctx, pg, db.NewSelect().Model((*TestModel)(nil)), &pagerpb.Page{
+        Limit: 2, Order: order, Direction: pagerpb.Direction_DIRECTION_BEFORE, Selector: &pagerpb.Page_Cursor{},
+    })
+    if err != nil { t.Fatal(err) }
+    if len(res.Items) != 2 || res.Items[0].ID != 4 || !res.HasMore || res.PrevCursor == "" || res.NextCursor != "" {
+        t.Fatalf("unexpected last page %+v", res)
+    }
+}
+
+func TestPaginate_ProjectionAndErrors(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error"})
+
+    res, err := Paginate[int64](ctx, pg, db.NewSelect().Model((*TestModel)(nil)).Column("id"), &pagerpb.Page{Selector: &pagerpb.Page_Page{Page: 2}})
+    if err != nil { t.Fatal(err) }
+    if !sameIDs(res.Items, []int64{3, 2}) || !res.HasMore || res.Page.GetPage() != 2 {
+        t.Fatalf("unexpected page %+v", res)
+    }
+
+    _, err = Paginate[TestModel](ctx, pg, db.NewSelect().Model((*TestModel)(nil)), &pagerpb.Page{Order: []*pagerpb.Order{{Key: "nope"}}})
+    expectInvalidRequest(t, err)
+}
@@ -0,0 +1,116 @@
+package pager
+
+import (
+    "context"
+    "testing"
+
+    pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+    "github.com/uptrace/bun"
+)
+
+type nameDTO struct {
+    ID   int64  `bun:"id"`
+    Name string `bun:"name"`
+}
+
+type createdDTO struct {
+    ID        int64 `bun:"id"`
+    CreatedAt int64 `bun:"created_at"`
+}
+
+func expectInvalidRequest(t *testing.T, err error) {
+    t.Helper()
+    if pe, ok := err.(*PagerError); !ok || pe.Code != ErrCodeInvalidRequest {
+        t.Fatalf("expected INVALID_REQUEST, got %v", err)
+    }
+}
+
+func TestApplyAndScan_ProjectionDTO(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    byScore := []*pagerpb.Order{{Key: "score", Asc: true}}
+    // score ASC: David(80) Bob(85) Eve(88) Alice(90) Charlie(95)
+    want := []int64{4, 2, 5, 1, 3}
+
+    // PK-only cursors: the DTO only needs the PK, the anchor fetch reads score from the model
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error"})
+    rows := walkPages[nameDTO](t, pg, func() *bun.SelectQuery {
+        return db.NewSelect().Model((*TestModel)(nil)).Column("id", "name")
+    }, byScore, 2, false)
+    var got []int64
+    for _, r := range rows { got = append(got, r.ID) }
+    if !sameIDs(got, want) || rows[0].Name != "David" {
+        t.Fatalf("unexpected DTO walk %v (%+v)", got, rows)
+    }
+
+    // Self-contained cursors need every order column in the DTO
+    self := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error", SelfContainedCursor: true})
+    var dtos []nameDTO
+    _, err := self.ApplyAndScan(context.Background(), db.NewSelect().Model((*TestModel)(nil)).Column("id", "name"),
+        &pagerpb.Page{Order: byScore}, &dtos)
+    expectInvalidRequest(t, err)
+
+    created := walkPages[createdDTO](t, self, func() *bun.SelectQuery {
+        return db.NewSelect().Model((*TestModel)(nil)).Column("id", "created_at")
+    }, []*pagerpb.Order{{Key: "created_at", Asc: false}}, 2, false)
+    got = got[:0]
+    for _, r := range created { got = append(got, r.ID) }
+    if !sameIDs(got, []int64{5, 4, 3, 2, 1}) {
+        t.Fatalf("unexpected self-contained DTO walk %v", got)
+    }
+}
+
+func TestApplyAndScan_MapDestination(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    for _, selfContained := range []bool{false, true} {
+        pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error", SelfContainedCursor: selfContained})
+        rows := walkPages[map[string]interface{}](t, pg, func() *bun.SelectQuery {
+            return db.NewSelect().Model((*TestModel)(nil)).Column("id", "score")
+        }, []*pagerpb.Order{{Key: "score", Asc: false}}, 2, false)
+        var got []int64
+        for _, r := range rows { got = append(got, r["id"].(int64)) }
+        if !sameIDs(got, []int64{3, 1, 5, 2, 4}) {
+            t.Fatalf("self=%v: unexpected map walk %v", selfContained, got)
+        }
+    }
+
+    // Rows without the PK cannot produce a cursor
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error"})
+    var rows []map[string]interface{}
+    _, err := pg.ApplyAndScan(context.Background(), db.NewSelect().Model((*TestModel)(nil)).Column("name"), &pagerpb.Page{}, &rows)
+    expectInvalidRequest(t, err)
+
+    // Without a query model the destination cannot describe the model
+    _, err = pg.ApplyAndScan(context.Background(), db.NewSelect().Table("test_models"), &pagerpb.Page{}, &rows)
+    expectInvalidRequest(t, err)
+}
+
+func TestApplyAndScan_ScalarDestination(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error"})
+    newQuery := func() *bun.SelectQuery { return db.NewSelect().Model((*TestModel)(nil)).Column("id") }
+
+    // The only order column (the PK) is the scanned value
+    got := walkPages[int64](t, pg, newQuery, nil, 2, false)
+    if !sameIDs(got, []int64{5, 4, 3, 2, 1}) {
+        t.Fatalf("unexpected scalar walk %v", got)
+    }
+    back := walkPages[int64](t, pg, newQuery, []*pagerpb.Order{{Key: "id", Asc: true}}, 2, true)
+    if !sameIDs(back, []int64{1, 2, 3, 4, 5}) {
+        t.Fatalf("unexpected scalar backward walk %v", back)
+    }
+
+    // Ordering by another column needs it in the row: cursor mode rejects, page mode works
+    var ids []int64
+    _, err := pg.ApplyAndScan(context.Background(), newQuery(), &pagerpb.Page{Order: []*pagerpb.Order{{Key: "score"}}}, &ids)
+    expectInvalidRequest(t, err)
+    out, err := pg.ApplyAndScan(context.Background(), newQuery(), &pagerpb.Page{
+        Order: []*pagerpb.Order{{Key: "score"}}, Selector: &pagerpb.Page_Page{Page: 1},
+    }, &ids)
+    if err != nil { t.Fatal(err) }
+    if !sameIDs(ids, []int64{3, 1}) || out.PageInfo.GetEndCursor() != "" {
+        t.Fatalf("unexpected page %v (end cursor %q)", ids, out.PageInfo.GetEndCursor())
+    }
+}
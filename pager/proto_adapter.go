@@ -16,10 +16,11 @@ import (
 // Flow:
 //  1) Validate selector presence (page/cursor) and destination type
 //  2) Infer model info and build order plan (with PK tiebreaker)
-//  3) Normalize limit (default/clamp)
+//  3) Normalize limit (default/clamp); count the base query if include_total
 //  4) Decide mode and apply WHERE (cursor) or OFFSET (page)
 //  5) Apply ORDER and LIMIT(+1), execute and trim
-//  6) Build next cursor (cursor mode)
+//     (direction=BEFORE runs the reversed plan and restores request order afterwards)
+//  6) Build next/prev cursors (cursor mode)
 func (p *Pager) ApplyAndScan(ctx context.Context, q *bun.SelectQuery, in *pagerpb.Page, dest interface{}) (*pagerpb.Page, error) {
     if in == nil {
         in = &pagerpb.Page{}
@@ -32,26 +33,16 @@ func (p *Pager) ApplyAndScan(ctx context.Context, q *bun.SelectQuery, in *pagerp
         return nil, NewInvalidRequestError("cannot specify both page and cursor")
     }
 
-    // Infer model info from destination
-    if dest == nil {
+    // Model info comes from q's model, else from the destination struct
+    // (bun's table schema, tag parser without a DB)
+    if dest == nil || reflect.ValueOf(dest).Kind() != reflect.Ptr || reflect.ValueOf(dest).IsNil() {
         return nil, NewInvalidRequestError("destination must be a non-nil pointer to slice")
     }
-    destType := reflect.TypeOf(dest)
-    if destType.Kind() != reflect.Ptr {
-        return nil, NewInvalidRequestError("destination must be a non-nil pointer to slice")
-    }
-    destType = destType.Elem()
+    destType := reflect.TypeOf(dest).Elem()
     if destType.Kind() != reflect.Slice {
         return nil, NewInvalidRequestError("destination must be a non-nil pointer to slice")
     }
-    modelType := destType.Elem()
-    var model interface{}
-    if modelType.Kind() == reflect.Ptr {
-        model = reflect.New(modelType.Elem()).Interface()
-    } else {
-        model = reflect.New(modelType).Interface()
-    }
-    modelInfo, err := InferModelInfo(model)
+    model, modelInfo, err := resolveModel(q, destType.Elem())
     if err != nil {
         if pe, ok := err.(*PagerError); ok {
             return nil, pe
@@ -59,18 +50,22 @@ func (p *Pager) ApplyAndScan(ctx context.Context, q *bun.SelectQuery, in *pagerp
         return nil, NewInternalError(fmt.Sprintf("failed to infer model info: %v", err))
     }
 
-    // Build order from proto (direct interface usage)
-    orders := make([]OrderSpecInterface, 0, len(in.Order))
-    for _, o := range in.Order {
-        if o == nil { continue }
-        orders = append(orders, o)
+    // Build order from proto (direct interface usage, or the order_by string)
+    orders, err := requestOrders(in)
+    if err != nil {
+        return nil, err
+    }
+    if in.GetOrderBy() != "" {
+        if err := checkOrderByColumns(orders, modelInfo, p.opts); err != nil {
+            return nil, err
+        }
     }
     if len(orders) == 0 && len(p.opts.DefaultOrderSpecs) > 0 {
         for _, spec := range p.opts.DefaultOrderSpecs {
             orders = append(orders, spec)
         }
     }
-    orderPlan, err := BuildOrderPlan(orders, modelInfo, p.opts.AllowedOrderKeys)
+    orderPlan, err := buildOrderPlan(orders, modelInfo, p.opts)
     if err != nil {
         if pe, ok := err.(*PagerError); ok {
             return nil, pe
@@ -78,43 +73,97 @@ func (p *Pager) ApplyAndScan(ctx context.Context, q *bun.SelectQuery, in *pagerp
         return nil, NewInternalError(fmt.Sprintf("failed to build order plan: %v", err))
     }
 
-    // Limit handling
-    limit, clamped := normalizeLimit(in.Limit, p.opts)
-    if clamped { p.logger.Warn("limit clamped", "from", in.Limit, "to", p.opts.MaxLimit) }
+    // The request filter restricts the base query (total included) before ordering
+    q, err = applyFilter(q, in.GetFilter(), modelInfo, p.opts)
+    if err != nil {
+        if pe, ok := err.(*PagerError); ok {
+            return nil, pe
+        }
+        return nil, NewInternalError(fmt.Sprintf("failed to build filter: %v", err))
+    }
+    queryFingerprint, err := requestQueryFingerprint(ctx, in)
+    if err != nil {
+        return nil, NewInternalError(fmt.Sprintf("failed to fingerprint query: %v", err))
+    }
 
-    // Determine mode and apply WHERE
-    mode := "offset"
+    // Projections (DTO, map or scalar rows) must carry what the cursor stores
+    shape, err := destShapeFor(q, destType.Elem(), reflect.TypeOf(model).Elem(), modelInfo)
+    if err != nil {
+        return nil, NewInternalError(fmt.Sprintf("failed to inspect destination: %v", err))
+    }
+    if key := shape.exprColumn(orderPlan); key != "" {
+        return nil, NewInvalidRequestError(fmt.Sprintf("order expression %q requires the model as destination", key))
+    }
+    missing := shape.missingColumn(orderPlan, cursorColumns(orderPlan, modelInfo, p.opts.SelfContainedCursor))
+    if missing != "" && !hasPage {
+        return nil, NewInvalidRequestError(fmt.Sprintf("destination lacks order column %q required by the cursor", missing))
+    }
+
+    backward := in.GetDirection() == pagerpb.Direction_DIRECTION_BEFORE
+    if backward && hasPage {
+        return nil, NewInvalidRequestError("direction BEFORE requires cursor mode")
+    }
+    // Rows before the cursor are fetched as rows after it under the reversed plan
+    queryPlan := orderPlan
+    if backward {
+        queryPlan = orderPlan.Reversed()
+    }
+
+    // Decode cursor early: it pins the order it was produced with and may carry the limit
+    var cd *CursorData
     if hasCursor {
-        mode = "cursor"
         // Empty cursor string means "from the beginning"; DecodeCursor returns nil
-        cd, err := DecodeCursor(cursorVal, modelInfo)
+        cd, err = p.DecodeCursor(cursorVal, modelInfo)
         if err != nil {
-            return nil, NewInvalidRequestError(fmt.Sprintf("invalid cursor: %v", err))
+            if pe, ok := err.(*PagerError); ok && (pe.Code == ErrCodeInvalidCursorSignature || pe.Code == ErrCodeInvalidCursor) {
+                return nil, pe
+            }
+            return nil, NewInvalidCursorError(fmt.Sprintf("invalid cursor: %v", err))
+        }
+        if cd != nil && cd.OrderFingerprint != "" && cd.OrderFingerprint != orderPlan.Fingerprint() {
+            return nil, NewCursorOrderMismatchError()
         }
+        // Cursors are bound to the query (filter) they were produced for
+        if cd != nil && cd.QueryFingerprint != queryFingerprint {
+            return nil, NewCursorQueryMismatchError()
+        }
+    }
+
+    // Limit handling (an unset limit on a cursor page reuses the cursor's limit)
+    reqLimit := in.Limit
+    if reqLimit == 0 && cd != nil && cd.Limit > 0 {
+        reqLimit = cd.Limit
+    }
+    limit, clamped := normalizeLimit(reqLimit, p.opts)
+    if clamped { p.logger.Warn("limit clamped", "from", reqLimit, "to", p.opts.MaxLimit) }
+
+    // Total runs on the caller's (filtered) query before any cursor predicate, offset, order or limit
+    var total pageTotal
+    if in.GetIncludeTotal() {
+        total, err = p.countTotal(ctx, q, in.GetTotalMode())
+        if err != nil {
+            return nil, NewInternalError(fmt.Sprintf("total count failed: %v", err))
+        }
+    }
+
+    // Determine mode and apply WHERE
+    mode := "offset"
+    if hasCursor {
+        mode = "cursor"
         if cd != nil && len(cd.Values) > 0 {
-            // Fetch anchor by single PK
-            anchor := reflect.New(reflect.Indirect(reflect.ValueOf(model)).Type()).Interface()
-            pkCol := firstPKColumn(modelInfo)
-            v, ok := cd.Values[pkCol]
-            if !ok { return nil, NewInvalidRequestError("invalid cursor: missing pk") }
-            // Normalize pk value to the model field type when possible
-            if idx, ok := modelInfo.FieldIndexByColumn[pkCol]; ok {
-                // Determine expected kind from model field
-                mt := reflect.Indirect(reflect.ValueOf(model)).Type().Field(idx).Type
-                v = coerceToKind(v, mt.Kind())
-            }
-            aq := q.DB().NewSelect().Model(anchor).Where(pkCol+" = ?", v).Limit(1)
-            if err := aq.Scan(ctx); err != nil {
-                if errors.Is(err, sql.ErrNoRows) {
-                    return nil, NewStaleCursorError()
+            var anchorVals map[string]interface{}
+            // Embedded values are only trusted on pagers that issue self-contained cursors
+            cd.SelfContained = p.opts.SelfContainedCursor && coversPlan(cd, orderPlan)
+            if cd.SelfContained {
+                // Cursor already carries every order column value: no anchor fetch
+                anchorVals = cd.Values
+            } else {
+                anchorVals, err = fetchAnchorValues(ctx, q, model, cd, orderPlan, modelInfo)
+                if err != nil {
+                    return nil, err
                 }
-                return nil, NewInternalError(fmt.Sprintf("anchor fetch failed: %v", err))
             }
-            anchorVals, err := ExtractRowValues(anchor, orderPlan, modelInfo)
-            if err != nil {
-                return nil, NewInternalError(fmt.Sprintf("failed to extract anchor values: %v", err))
-            }
-            where, args2, err := BuildCursorWhere(&CursorData{Values: anchorVals}, orderPlan)
+            where, args2, err := p.cursorWhere(q, &CursorData{Values: anchorVals}, queryPlan)
             if err != nil {
                 return nil, NewInternalError(fmt.Sprintf("failed to build cursor where: %v", err))
             }
@@ -135,8 +184,13 @@ func (p *Pager) ApplyAndScan(ctx context.Context, q *bun.SelectQuery, in *pagerp
         mode = "cursor"
     }
 
+    // Join relations referenced by the order (no-op when the caller already did)
+    for _, name := range planRelations(orderPlan, modelInfo) {
+        q = q.Relation(name)
+    }
+
     // Apply order and limit(+1)
-    q = ApplyOrderToQuery(q, orderPlan)
+    q = ApplyOrderToQuery(q, queryPlan)
     q = q.Limit(limit + 1)
 
     // Execute
@@ -144,7 +198,7 @@ func (p *Pager) ApplyAndScan(ctx context.Context, q *bun.SelectQuery, in *pagerp
         return nil, NewInternalError(fmt.Sprintf("query execution failed: %v", err))
     }
 
-    // Trim and build next cursor
+    // Trim; hasMore means more rows exist in the query direction
     destValue := reflect.ValueOf(dest).Elem()
     rowCount := destValue.Len()
     hasMore := false
@@ -153,26 +207,49 @@ func (p *Pager) ApplyAndScan(ctx context.Context, q *bun.SelectQuery, in *pagerp
         destValue.Set(destValue.Slice(0, limit))
         rowCount = limit
     }
+    if backward {
+        reverseSlice(destValue)
+    }
+
+    info := PageInfo{Count: rowCount, Limit: limit}
+    if in.GetIncludeTotal() {
+        info.TotalCount, info.TotalCapped, info.TotalEstimated = total.Count, total.Capped, total.Estimated
+        info.TotalPages = totalPages(total.Count, limit)
+    }
+    if rowCount > 0 && missing == "" {
+        hdr := cursorHeader{Limit: uint32(limit), Query: queryFingerprint}
+        first, last := shape.values(destValue.Index(0), orderPlan), shape.values(destValue.Index(rowCount-1), orderPlan)
+        if column := missingValue(first, orderPlan, modelInfo, p.opts.SelfContainedCursor); column == "" {
+            info.StartCursor = p.rowCursor(first, orderPlan, modelInfo, hdr)
+            info.EndCursor = p.rowCursor(last, orderPlan, modelInfo, hdr)
+        } else if mode == "cursor" {
+            return nil, NewInvalidRequestError(fmt.Sprintf("destination lacks order column %q required by the cursor", column))
+        }
+    }
+    if mode == "cursor" {
+        // A non-empty request cursor means rows exist on its other side. An empty page has
+        // no row to turn back from (the request cursor would skip its own anchor row).
+        behind := cursorVal != "" && rowCount > 0
+        info.HasNext, info.HasPrevious = hasMore, behind
+        if backward {
+            info.HasNext, info.HasPrevious = behind, hasMore
+        }
+    } else {
+        info.HasNext, info.HasPrevious = hasMore, pageVal > 1
+    }
 
-    out := &pagerpb.Page{Limit: uint32(limit), Order: in.Order}
+    out := &pagerpb.Page{
+        Limit: uint32(limit), Order: in.Order, OrderBy: in.OrderBy, Direction: in.Direction,
+        IncludeTotal: in.IncludeTotal, TotalMode: in.TotalMode, Filter: in.Filter, PageInfo: info.Proto(),
+    }
     
     if mode == "cursor" {
-        if rowCount > 0 && hasMore {
-            // Generate next cursor
-            lastRow := destValue.Index(rowCount - 1)
-            if lastRow.Kind() == reflect.Ptr { lastRow = lastRow.Elem() }
-            values := make(map[string]interface{})
-            for _, item := range orderPlan.Items {
-                if idx, ok := modelInfo.FieldIndexByColumn[item.Column]; ok {
-                    values[item.Column] = lastRow.Field(idx).Interface()
-                }
-            }
-            if next, err := EncodeCursor(orderPlan, values, modelInfo); err == nil {
-                out.Selector = &pagerpb.Page_Cursor{Cursor: next}
-            }
-        } else {
-            // No next cursor
-            out.Selector = &pagerpb.Page_Cursor{Cursor: ""}
+        out.Selector = &pagerpb.Page_Cursor{Cursor: ""}
+        if info.HasNext {
+            out.Selector = &pagerpb.Page_Cursor{Cursor: info.EndCursor}
+        }
+        if info.HasPrevious {
+            out.PrevCursor = info.StartCursor
         }
     } else {
         // Page mode - echo back the page number
@@ -182,6 +259,129 @@ func (p *Pager) ApplyAndScan(ctx context.Context, q *bun.SelectQuery, in *pagerp
     return out, nil
 }
 
+// cursorWhere renders the cursor predicate with the configured strategy.
+func (p *Pager) cursorWhere(q *bun.SelectQuery, cd *CursorData, orderPlan *OrderPlan) (string, []interface{}, error) {
+    switch p.opts.CursorWhere {
+    case CursorWhereTuple:
+        if supportsRowValues(q.Dialect().Name()) {
+            if where, args, ok := BuildTupleCursorWhere(cd, orderPlan); ok {
+                return where, args, nil
+            }
+        }
+    case CursorWhereFactored:
+        if where, args, ok := BuildFactoredCursorWhere(cd, orderPlan); ok {
+            return where, args, nil
+        }
+    }
+    return BuildCursorWhere(cd, orderPlan)
+}
+
+// rowCursor encodes the cursor of a scanned row's values; encoding failures are logged and yield "".
+func (p *Pager) rowCursor(values map[string]interface{}, orderPlan *OrderPlan, modelInfo *ModelInfo, hdr cursorHeader) string {
+    token, err := p.encodeCursor(orderPlan, values, modelInfo, hdr)
+    if err != nil {
+        p.logger.Warn("cursor encoding failed", "err", err)
+        return ""
+    }
+    return token
+}
+
+// missingValue returns the first cursor column absent from row values ("" if none);
+// map rows are only known to lack a column once scanned.
+func missingValue(values map[string]interface{}, orderPlan *OrderPlan, modelInfo *ModelInfo, selfContained bool) string {
+    for _, column := range cursorColumns(orderPlan, modelInfo, selfContained) {
+        if _, ok := values[column]; !ok {
+            return column
+        }
+    }
+    return ""
+}
+
+// reverseSlice reverses a slice value in place.
+func reverseSlice(v reflect.Value) {
+    swap := reflect.Swapper(v.Interface())
+    for i, j := 0, v.Len()-1; i < j; i, j = i+1, j-1 {
+        swap(i, j)
+    }
+}
+
+// coversPlan reports whether the cursor carries a value for every OrderPlan column.
+func coversPlan(cd *CursorData, orderPlan *OrderPlan) bool {
+    for _, item := range orderPlan.Items {
+        if _, ok := cd.Values[item.Column]; !ok {
+            return false
+        }
+    }
+    return true
+}
+
+// fetchAnchorValues re-reads the anchor row by the cursor's PK tuple and returns its order column values.
+// A missing anchor (e.g. deleted row) yields STALE_CURSOR.
+func fetchAnchorValues(ctx context.Context, q *bun.SelectQuery, model interface{}, cd *CursorData, orderPlan *OrderPlan, modelInfo *ModelInfo) (map[string]interface{}, error) {
+    // Fetch anchor by the full PK tuple
+    anchor := reflect.New(reflect.Indirect(reflect.ValueOf(model)).Type()).Interface()
+    aq := q.DB().NewSelect().Model(anchor)
+    for _, pkCol := range pkColumns(modelInfo) {
+        v, ok := cd.Values[pkCol]
+        if !ok { return nil, NewInvalidCursorError("invalid cursor: missing pk") }
+        // Normalize pk value to the model field type when possible
+        if path, ok := modelInfo.FieldIndexByColumn[pkCol]; ok {
+            // Determine expected kind from model field
+            mt := reflect.Indirect(reflect.ValueOf(model)).Type().FieldByIndex(path).Type
+            v = coerceToKind(v, mt.Kind())
+        }
+        aq = aq.Where("?TableAlias.? = ?", bun.Ident(pkCol), v)
+    }
+    // Relation columns come from the cursor; join only those it does not carry (legacy tokens)
+    missing := &OrderPlan{}
+    for _, item := range orderPlan.Items {
+        if _, ok := item.relationAlias(); ok {
+            if _, carried := cd.Values[item.Column]; !carried {
+                missing.Items = append(missing.Items, item)
+            }
+        }
+    }
+    for _, name := range planRelations(missing, modelInfo) {
+        aq = aq.Relation(name)
+    }
+    aq = aq.Limit(1)
+    if err := aq.Scan(ctx); err != nil {
+        if errors.Is(err, sql.ErrNoRows) {
+            return nil, NewStaleCursorError()
+        }
+        return nil, NewInternalError(fmt.Sprintf("anchor fetch failed: %v", err))
+    }
+    anchorVals, err := ExtractRowValues(anchor, orderPlan, modelInfo)
+    if err != nil {
+        return nil, NewInternalError(fmt.Sprintf("failed to extract anchor values: %v", err))
+    }
+    for _, item := range orderPlan.Items {
+        if _, ok := item.relationAlias(); ok {
+            if v, carried := cd.Values[item.Column]; carried {
+                anchorVals[item.Column] = v
+            }
+        }
+    }
+    return anchorVals, nil
+}
+
+// planRelations returns the Go field names of the relations whose columns the plan orders by.
+func planRelations(orderPlan *OrderPlan, modelInfo *ModelInfo) []string {
+    var names []string
+    seen := map[string]bool{}
+    for _, item := range orderPlan.Items {
+        alias, ok := item.relationAlias()
+        if !ok || seen[alias] {
+            continue
+        }
+        seen[alias] = true
+        if name, ok := modelInfo.Relations[alias]; ok {
+            names = append(names, name)
+        }
+    }
+    return names
+}
+
 // detectPresence determines whether page/cursor selectors were explicitly provided.
 func detectPresence(in *pagerpb.Page) (hasCursor, hasPage bool) {
     if in == nil { return false, false }
@@ -0,0 +1,72 @@
+package pager
+
+import (
+    "context"
+    "crypto/sha256"
+    "encoding/base64"
+
+    pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+    "google.golang.org/protobuf/proto"
+)
+
+// queryFingerprintKey holds the hashed caller fingerprint of a request context.
+type queryFingerprintKey struct{}
+
+// filterFingerprintKey holds the hashed fingerprint of a filter applied outside
+// Page.filter (the filter string of ApplyAndScanList).
+type filterFingerprintKey struct{}
+
+// WithQueryFingerprint binds the cursors of requests run with ctx to fingerprint, a
+// caller-chosen description of the filters added to q directly (e.g. "status=1" or the
+// raw filter string). Cursors carry a hash of it together with Page.filter's; replaying
+// one after either changed fails with CURSOR_QUERY_MISMATCH.
+func WithQueryFingerprint(ctx context.Context, fingerprint string) context.Context {
+    hashed := ""
+    if fingerprint != "" {
+        hashed = hashQueryFingerprint([]byte("c:" + fingerprint))
+    }
+    return context.WithValue(ctx, queryFingerprintKey{}, hashed)
+}
+
+// withFilterFingerprint binds cursors to f, a filter applied to q outside Page.filter.
+func withFilterFingerprint(ctx context.Context, f *pagerpb.Filter) (context.Context, error) {
+    fp, err := filterFingerprint(f)
+    if err != nil {
+        return ctx, err
+    }
+    return context.WithValue(ctx, filterFingerprintKey{}, fp), nil
+}
+
+// requestQueryFingerprint returns the hashed fingerprint the request's cursors are bound
+// to: the caller's, any filter applied outside Page.filter and in.Filter hashed together
+// ("" when there is none of them).
+func requestQueryFingerprint(ctx context.Context, in *pagerpb.Page) (string, error) {
+    caller, _ := ctx.Value(queryFingerprintKey{}).(string)
+    applied, _ := ctx.Value(filterFingerprintKey{}).(string)
+    filter, err := filterFingerprint(in.GetFilter())
+    if err != nil {
+        return "", err
+    }
+    if caller == "" && applied == "" {
+        return filter, nil
+    }
+    return hashQueryFingerprint([]byte(caller + "|" + applied + "|" + filter)), nil
+}
+
+// filterFingerprint hashes the deterministic encoding of a filter ("" for none).
+func filterFingerprint(f *pagerpb.Filter) (string, error) {
+    if f.GetKind() == nil {
+        return "", nil
+    }
+    b, err := proto.MarshalOptions{Deterministic: true}.Marshal(f)
+    if err != nil {
+        return "", err
+    }
+    return hashQueryFingerprint(append([]byte("f:"), b...)), nil
+}
+
+// hashQueryFingerprint keeps tokens short and the fingerprint's content out of them.
+func hashQueryFingerprint(b []byte) string {
+    sum := sha256.Sum256(b)
+    return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
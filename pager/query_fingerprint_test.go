@@ -0,0 +1,119 @@
+package pager
+
+import (
+    "context"
+    "encoding/base64"
+    "strings"
+    "testing"
+
+    pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+    "google.golang.org/protobuf/reflect/protoreflect"
+    "google.golang.org/protobuf/types/dynamicpb"
+)
+
+func expectQueryMismatch(t *testing.T, err error) {
+    t.Helper()
+    if pe, ok := err.(*PagerError); !ok || pe.Code != ErrCodeCursorQueryMismatch {
+        t.Fatalf("expected CURSOR_QUERY_MISMATCH, got %v", err)
+    }
+}
+
+func TestApplyAndScan_CursorBoundToFilter(t *testing.T) {
+    for _, selfContained := range []bool{false, true} {
+        db := setupTestDB(t)
+        ctx := context.Background()
+        pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error", SelfContainedCursor: selfContained})
+        order := []*pagerpb.Order{{Key: "score", Asc: true}}
+        page := func(filter *pagerpb.Filter, cursor string) ([]TestModel, *pagerpb.Page, error) {
+            var rows []TestModel
+            in := &pagerpb.Page{Order: order, Filter: filter, Selector: &pagerpb.Page_Cursor{Cursor: cursor}}
+            out, err := pg.ApplyAndScan(ctx, db.NewSelect().Model((*TestModel)(nil)), in, &rows)
+            return rows, out, err
+        }
+
+        _, out, err := page(cond("score", pagerpb.FilterOp_FILTER_OP_GTE, intVal(85)), "")
+        if err != nil { t.Fatal(err) }
+        cursor := out.GetCursor()
+
+        // An equal filter (a different message) continues the walk
+        rows, _, err := page(cond("score", pagerpb.FilterOp_FILTER_OP_GTE, intVal(85)), cursor)
+        if err != nil { t.Fatal(err) }
+        if !sameIDs(ids(rows), []int64{1, 3}) {
+            t.Fatalf("unexpected second page %v", ids(rows))
+        }
+        _, _, err = page(cond("score", pagerpb.FilterOp_FILTER_OP_GTE, intVal(90)), cursor)
+        expectQueryMismatch(t, err)
+        _, _, err = page(nil, cursor)
+        expectQueryMismatch(t, err)
+
+        // Cursors of unfiltered queries are not accepted with a filter either
+        _, out, err = page(nil, "")
+        if err != nil { t.Fatal(err) }
+        _, _, err = page(cond("score", pagerpb.FilterOp_FILTER_OP_GTE, intVal(85)), out.GetCursor())
+        expectQueryMismatch(t, err)
+        db.Close()
+    }
+}
+
+func TestApplyAndScan_CallerQueryFingerprint(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error"})
+    page := func(ctx context.Context, cursor string) error {
+        var rows []TestModel
+        _, err := pg.ApplyAndScan(ctx, db.NewSelect().Model((*TestModel)(nil)), &pagerpb.Page{Selector: &pagerpb.Page_Cursor{Cursor: cursor}}, &rows)
+        return err
+    }
+
+    var rows []TestModel
+    res, err := Paginate[TestModel](WithQueryFingerprint(context.Background(), "status=1"), pg, db.NewSelect().Model((*TestModel)(nil)), nil)
+    if err != nil { t.Fatal(err) }
+    raw, _ := base64.URLEncoding.DecodeString(res.NextCursor)
+    if strings.Contains(string(raw), "status") || !strings.Contains(string(raw), `"q":`) {
+        t.Fatalf("cursor must carry the hashed fingerprint only: %s", raw)
+    }
+
+    if err := page(WithQueryFingerprint(context.Background(), "status=1"), res.NextCursor); err != nil { t.Fatal(err) }
+    expectQueryMismatch(t, page(WithQueryFingerprint(context.Background(), "status=2"), res.NextCursor))
+    expectQueryMismatch(t, page(context.Background(), res.NextCursor))
+
+    // The caller's fingerprint combines with the one computed from Page.filter
+    filtered := func(ctx context.Context, score int64, cursor string) (*pagerpb.Page, error) {
+        in := &pagerpb.Page{Filter: cond("score", pagerpb.FilterOp_FILTER_OP_GTE, intVal(score)), Selector: &pagerpb.Page_Cursor{Cursor: cursor}}
+        return pg.ApplyAndScan(ctx, db.NewSelect().Model((*TestModel)(nil)), in, &rows)
+    }
+    ctx := WithQueryFingerprint(context.Background(), "status=1")
+    out, err := filtered(ctx, 80, "")
+    if err != nil { t.Fatal(err) }
+    if _, err := filtered(ctx, 80, out.GetCursor()); err != nil { t.Fatal(err) }
+    _, err = filtered(ctx, 0, out.GetCursor())
+    expectQueryMismatch(t, err)
+    _, err = filtered(WithQueryFingerprint(context.Background(), "status=2"), 80, out.GetCursor())
+    expectQueryMismatch(t, err)
+    _, err = filtered(context.Background(), 80, out.GetCursor())
+    expectQueryMismatch(t, err)
+}
+
+func TestApplyAndScanList_TokenBoundToFilter(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error"})
+    reqDesc, respDesc := listMessages(t)
+    list := func(filter, token string) (string, error) {
+        req, resp := dynamicpb.NewMessage(reqDesc), dynamicpb.NewMessage(respDesc)
+        req.Set(reqDesc.Fields().ByName("filter"), protoreflect.ValueOfString(filter))
+        req.Set(reqDesc.Fields().ByName("page_token"), protoreflect.ValueOfString(token))
+        var rows []TestModel
+        _, err := pg.ApplyAndScanList(ctx, db.NewSelect().Model((*TestModel)(nil)), req, resp, &rows, ListOptions{})
+        return resp.Get(respDesc.Fields().ByName("next_page_token")).String(), err
+    }
+
+    token, err := list(`score >= 85`, "")
+    if err != nil { t.Fatal(err) }
+    if _, err := list(`score>=85`, token); err != nil { t.Fatal(err) }
+    _, err = list(`score >= 90`, token)
+    expectQueryMismatch(t, err)
+    _, err = list("", token)
+    expectQueryMismatch(t, err)
+}
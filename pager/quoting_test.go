@@ -0,0 +1,70 @@
+package pager
+
+import (
+    "context"
+    "testing"
+
+    pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+    "github.com/uptrace/bun"
+)
+
+type reservedItem struct {
+    ID    int64  `bun:"id,pk,autoincrement"`
+    Order int    `bun:"order"`
+    Group string `bun:"group"`
+}
+
+type joinAuthor struct {
+    ID   int64  `bun:"id,pk,autoincrement"`
+    Name string `bun:"name"`
+}
+
+type joinBook struct {
+    ID        int64       `bun:"id,pk,autoincrement"`
+    AuthorID  int64       `bun:"author_id"`
+    CreatedAt int64       `bun:"created_at"`
+    Author    *joinAuthor `bun:"rel:belongs-to,join:author_id=id"`
+}
+
+func TestApplyAndScan_ReservedWordColumns(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    if _, err := db.NewCreateTable().Model((*reservedItem)(nil)).Exec(ctx); err != nil { t.Fatal(err) }
+    items := []reservedItem{{Order: 2, Group: "b"}, {Order: 1, Group: "a"}, {Order: 2, Group: "a"}, {Order: 3, Group: "c"}}
+    if _, err := db.NewInsert().Model(&items).Exec(ctx); err != nil { t.Fatal(err) }
+
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error"})
+    order := []*pagerpb.Order{{Key: "order", Asc: true}, {Key: "group", Asc: true}}
+    all := walkPages[reservedItem](t, pg, func() *bun.SelectQuery { return db.NewSelect().Model((*reservedItem)(nil)) }, order, 2, false)
+    var got []int64
+    for _, it := range all { got = append(got, it.ID) }
+    if !sameIDs(got, []int64{2, 3, 1, 4}) {
+        t.Fatalf("expected order (1,a),(2,a),(2,b),(3,c) -> ids [2 3 1 4], got %v", got)
+    }
+}
+
+func TestApplyAndScan_RelationJoinQualifiesColumns(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    for _, m := range []interface{}{(*joinAuthor)(nil), (*joinBook)(nil)} {
+        if _, err := db.NewCreateTable().Model(m).Exec(ctx); err != nil { t.Fatal(err) }
+    }
+    authors := []joinAuthor{{Name: "Ann"}, {Name: "Ben"}}
+    if _, err := db.NewInsert().Model(&authors).Exec(ctx); err != nil { t.Fatal(err) }
+    books := []joinBook{{AuthorID: 1, CreatedAt: 10}, {AuthorID: 2, CreatedAt: 20}, {AuthorID: 1, CreatedAt: 30}, {AuthorID: 2, CreatedAt: 40}}
+    if _, err := db.NewInsert().Model(&books).Exec(ctx); err != nil { t.Fatal(err) }
+
+    // Both tables have "id": unqualified ORDER BY / WHERE would be ambiguous
+    pg := New(&Options{DefaultLimit: 3, MaxLimit: 10, LogLevel: "error"})
+    all := walkPages[joinBook](t, pg, func() *bun.SelectQuery { return db.NewSelect().Model((*joinBook)(nil)).Relation("Author") }, nil, 3, false)
+    if len(all) != 4 || all[0].ID != 4 || all[3].ID != 1 {
+        t.Fatalf("expected books 4..1 by id DESC, got %+v", all)
+    }
+    for _, b := range all {
+        if b.Author == nil || b.Author.ID != b.AuthorID {
+            t.Fatalf("expected joined author for book %d, got %+v", b.ID, b.Author)
+        }
+    }
+}
@@ -0,0 +1,113 @@
+package pager
+
+import (
+    "context"
+    "encoding/base64"
+    "testing"
+
+    pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+    "github.com/uptrace/bun"
+)
+
+func setupBooksDB(t *testing.T) *bun.DB {
+    db := setupTestDB(t)
+    ctx := context.Background()
+    for _, m := range []interface{}{(*joinAuthor)(nil), (*joinBook)(nil)} {
+        if _, err := db.NewCreateTable().Model(m).Exec(ctx); err != nil { t.Fatal(err) }
+    }
+    authors := []joinAuthor{{Name: "Ben"}, {Name: "Ann"}}
+    if _, err := db.NewInsert().Model(&authors).Exec(ctx); err != nil { t.Fatal(err) }
+    // Book 5 has no author: its author.name is NULL through the LEFT JOIN
+    books := []joinBook{{AuthorID: 1, CreatedAt: 10}, {AuthorID: 2, CreatedAt: 20}, {AuthorID: 1, CreatedAt: 30}, {AuthorID: 2, CreatedAt: 40}, {AuthorID: 99, CreatedAt: 50}}
+    if _, err := db.NewInsert().Model(&books).Exec(ctx); err != nil { t.Fatal(err) }
+    return db
+}
+
+func TestInferModelInfo_RelationColumns(t *testing.T) {
+    info, err := InferModelInfo(&joinBook{})
+    if err != nil { t.Fatal(err) }
+    if info.KeyToColumn["author.name"] != "author.name" || info.Relations["author"] != "Author" {
+        t.Fatalf("expected author.name relation key, got %v / %v", info.KeyToColumn, info.Relations)
+    }
+    if p := info.RelationFieldPaths["author.name"]; len(p) != 2 || p[0] != 3 || p[1] != 1 {
+        t.Fatalf("unexpected field path %v", p)
+    }
+    if !info.NullableColumns["author.name"] {
+        t.Fatal("relation columns are nullable (LEFT JOIN)")
+    }
+    if _, ok := info.KeyToColumn["rel:belongs-to"]; ok {
+        t.Fatal("relation tag must not be recorded as a column")
+    }
+}
+
+func TestApplyAndScan_OrderByRelationColumn(t *testing.T) {
+    for _, tc := range []struct {
+        name          string
+        selfContained bool
+        withRelation  bool
+    }{
+        {"pk-only/auto-join", false, false},
+        {"pk-only/caller-join", false, true},
+        {"self-contained/auto-join", true, false},
+    } {
+        t.Run(tc.name, func(t *testing.T) {
+            db := setupBooksDB(t)
+            defer db.Close()
+            ctx := context.Background()
+            pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error", SelfContainedCursor: tc.selfContained})
+            order := []*pagerpb.Order{{Key: "author.name", Asc: true}}
+            newQuery := func() *bun.SelectQuery {
+                q := db.NewSelect().Model((*joinBook)(nil))
+                if tc.withRelation { q = q.Relation("Author") }
+                return q
+            }
+
+            var first []joinBook
+            out, err := pg.ApplyAndScan(ctx, newQuery(), &pagerpb.Page{Limit: 2, Order: order}, &first)
+            if err != nil { t.Fatal(err) }
+            cd, err := pg.DecodeCursor(out.GetCursor(), mustModelInfo(t, &joinBook{}))
+            if err != nil { t.Fatal(err) }
+            if _, ok := cd.Values["author.name"]; !ok {
+                t.Fatalf("cursor must carry the relation column value, got %v", cd.Values)
+            }
+
+            all := walkPages[joinBook](t, pg, newQuery, order, 2, false)
+            // Ann (author 2): books 4, 2; Ben (author 1): books 3, 1; no author (NULL last): 5
+            var got []int64
+            for _, b := range all { got = append(got, b.ID) }
+            if !sameIDs(got, []int64{4, 2, 3, 1, 5}) {
+                t.Fatalf("expected [4 2 3 1 5], got %v", got)
+            }
+            if all[0].Author == nil || all[0].Author.Name != "Ann" {
+                t.Fatalf("expected the relation to be loaded, got %+v", all[0].Author)
+            }
+        })
+    }
+}
+
+func mustModelInfo(t *testing.T, model interface{}) *ModelInfo {
+    t.Helper()
+    info, err := InferModelInfo(model)
+    if err != nil { t.Fatal(err) }
+    return info
+}
+
+func TestApplyAndScan_RelationColumnFromLegacyCursor(t *testing.T) {
+    db := setupBooksDB(t)
+    defer db.Close()
+    pg := New(&Options{DefaultLimit: 5, MaxLimit: 10, LogLevel: "error"})
+
+    // A PK-only token carries no relation value: the anchor fetch joins the relation itself
+    var rows []joinBook
+    legacy := base64.URLEncoding.EncodeToString([]byte("3"))
+    if _, err := pg.ApplyAndScan(context.Background(), db.NewSelect().Model((*joinBook)(nil)), &pagerpb.Page{
+        Limit: 5, Order: []*pagerpb.Order{{Key: "author.name", Asc: true}}, Selector: &pagerpb.Page_Cursor{Cursor: legacy},
+    }, &rows); err != nil {
+        t.Fatal(err)
+    }
+    var got []int64
+    for _, b := range rows { got = append(got, b.ID) }
+    if !sameIDs(got, []int64{1, 5}) {
+        t.Fatalf("expected rows after book 3 [1 5], got %v", got)
+    }
+}
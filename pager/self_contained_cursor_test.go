@@ -0,0 +1,170 @@
+package pager
+
+import (
+    "bytes"
+    "context"
+    "encoding/base64"
+    "encoding/json"
+    "testing"
+    "time"
+
+    pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+)
+
+func TestSelfContainedCursor_TypedRoundTrip(t *testing.T) {
+    ts := time.Date(2025, 9, 5, 12, 30, 0, 123456789, time.FixedZone("KST", 9*3600))
+    name := "Bob"
+    plan := &OrderPlan{Items: []OrderItem{
+        {Column: "i", Direction: "DESC"}, {Column: "u", Direction: "DESC"}, {Column: "f", Direction: "DESC"},
+        {Column: "s", Direction: "DESC"}, {Column: "b", Direction: "DESC"}, {Column: "t", Direction: "DESC"},
+        {Column: "x", Direction: "DESC"}, {Column: "p", Direction: "DESC"}, {Column: "n", Direction: "DESC"},
+    }}
+    row := map[string]interface{}{
+        "i": int32(-7), "u": uint64(1 << 63), "f": 0.1, "s": "12", "b": true,
+        "t": ts, "x": []byte{0, 1, 2}, "p": &name, "n": (*string)(nil),
+    }
+    token, err := EncodeSelfContainedCursor(plan, row)
+    if err != nil { t.Fatal(err) }
+
+    cd, err := DecodeCursor(token, &ModelInfo{PKColumns: []string{"i"}})
+    if err != nil { t.Fatal(err) }
+    if cd.Values["i"] != int64(-7) { t.Fatalf("int: got %#v", cd.Values["i"]) }
+    if cd.Values["u"] != uint64(1<<63) { t.Fatalf("uint: got %#v", cd.Values["u"]) }
+    if cd.Values["f"] != 0.1 { t.Fatalf("float: got %#v", cd.Values["f"]) }
+    // numeric-looking strings must stay strings
+    if cd.Values["s"] != "12" { t.Fatalf("string: got %#v", cd.Values["s"]) }
+    if cd.Values["b"] != true { t.Fatalf("bool: got %#v", cd.Values["b"]) }
+    if got, ok := cd.Values["t"].(time.Time); !ok || !got.Equal(ts) { t.Fatalf("time: got %#v", cd.Values["t"]) }
+    if got, ok := cd.Values["x"].([]byte); !ok || !bytes.Equal(got, []byte{0, 1, 2}) { t.Fatalf("bytes: got %#v", cd.Values["x"]) }
+    if cd.Values["p"] != "Bob" { t.Fatalf("pointer: got %#v", cd.Values["p"]) }
+    if v, ok := cd.Values["n"]; !ok || v != nil { t.Fatalf("null: got %#v (present=%v)", v, ok) }
+}
+
+func TestSelfContainedCursor_MissingColumnErrors(t *testing.T) {
+    plan := &OrderPlan{Items: []OrderItem{{Column: "created_at", Direction: "DESC"}, {Column: "id", Direction: "DESC"}}}
+    if _, err := EncodeSelfContainedCursor(plan, map[string]interface{}{"id": 1}); err == nil {
+        t.Fatal("expected error when an order column value is missing")
+    }
+}
+
+func TestApplyAndScan_SelfContainedCursor_SurvivesDeletedAnchor(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error", SelfContainedCursor: true})
+
+    in := &pagerpb.Page{Limit: 2, Order: []*pagerpb.Order{{Key: "created_at", Asc: false}}}
+    var first []TestModel
+    out, err := pg.ApplyAndScan(ctx, db.NewSelect().Model(&TestModel{}), in, &first)
+    if err != nil { t.Fatal(err) }
+    cursor, ok := out.Selector.(*pagerpb.Page_Cursor)
+    if !ok || cursor.Cursor == "" { t.Fatal("expected next cursor") }
+
+    // Delete the last row seen: a PK-only cursor would now be STALE_CURSOR
+    last := first[len(first)-1]
+    if _, err := db.NewDelete().Model((*TestModel)(nil)).Where("id = ?", last.ID).Exec(ctx); err != nil {
+        t.Fatal(err)
+    }
+
+    var next []TestModel
+    in2 := &pagerpb.Page{Limit: 2, Order: in.Order, Selector: &pagerpb.Page_Cursor{Cursor: cursor.Cursor}}
+    if _, err := pg.ApplyAndScan(ctx, db.NewSelect().Model(&TestModel{}), in2, &next); err != nil {
+        t.Fatalf("expected pagination to continue after anchor deletion, got %v", err)
+    }
+    if len(next) != 2 { t.Fatalf("expected 2 rows, got %d", len(next)) }
+    if next[0].CreatedAt >= last.CreatedAt {
+        t.Fatalf("expected rows strictly after the deleted anchor, got created_at=%d", next[0].CreatedAt)
+    }
+}
+
+func TestApplyAndScan_SelfContainedCursor_RejectsOrderWithUnknownColumns(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error", SelfContainedCursor: true})
+
+    var rows []TestModel
+    out, err := pg.ApplyAndScan(ctx, db.NewSelect().Model(&TestModel{}), &pagerpb.Page{Limit: 2, Order: []*pagerpb.Order{{Key: "created_at", Asc: false}}}, &rows)
+    if err != nil { t.Fatal(err) }
+    cursor := out.Selector.(*pagerpb.Page_Cursor).Cursor
+
+    // The cursor carries (created_at, id); ordering by score cannot be resolved from it
+    in2 := &pagerpb.Page{Limit: 2, Order: []*pagerpb.Order{{Key: "score", Asc: false}}, Selector: &pagerpb.Page_Cursor{Cursor: cursor}}
+    if _, err := pg.ApplyAndScan(ctx, db.NewSelect().Model(&TestModel{}), in2, &rows); err == nil {
+        t.Fatal("expected invalid cursor error for an order the cursor does not cover")
+    }
+}
+
+func TestApplyAndScan_ForgedSelfContainedCursorOnDefaultPager(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error"})
+    order := []*pagerpb.Order{{Key: "created_at", Asc: true}}
+
+    var rows []TestModel
+    out, err := pg.ApplyAndScan(ctx, db.NewSelect().Model(&TestModel{}), &pagerpb.Page{Order: order}, &rows)
+    if err != nil { t.Fatal(err) }
+    raw, err := base64.URLEncoding.DecodeString(out.GetCursor())
+    if err != nil { t.Fatal(err) }
+
+    // An unsigned envelope carrying every order column for a row that does not exist:
+    // a pager without SelfContainedCursor still re-reads the anchor
+    var env cursorEnvelope
+    if err := json.Unmarshal(raw, &env); err != nil { t.Fatal(err) }
+    env.Keys = []string{"created_at", "id"}
+    env.Values = []cursorValue{{T: "i", V: "3500"}, {T: "i", V: "99"}}
+    b, err := json.Marshal(env)
+    if err != nil { t.Fatal(err) }
+    in := &pagerpb.Page{Order: order, Selector: &pagerpb.Page_Cursor{Cursor: base64.URLEncoding.EncodeToString(b)}}
+    _, err = pg.ApplyAndScan(ctx, db.NewSelect().Model(&TestModel{}), in, &rows)
+    if pagerErrorCode(err) != ErrCodeStaleCursor {
+        t.Fatalf("expected STALE_CURSOR, got %v", err)
+    }
+}
+
+func TestApplyAndScan_SelfContainedCursor_ValuesMatchColumnTypes(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error", SelfContainedCursor: true})
+    order := []*pagerpb.Order{{Key: "created_at", Asc: true}}
+
+    var rows []TestModel
+    out, err := pg.ApplyAndScan(ctx, db.NewSelect().Model(&TestModel{}), &pagerpb.Page{Order: order}, &rows)
+    if err != nil { t.Fatal(err) }
+    raw, err := base64.URLEncoding.DecodeString(out.GetCursor())
+    if err != nil { t.Fatal(err) }
+    // forge rewrites the cursor's (created_at, id) values, as an unsigned client could
+    forge := func(values ...cursorValue) string {
+        var env cursorEnvelope
+        if err := json.Unmarshal(raw, &env); err != nil { t.Fatal(err) }
+        env.Values = values
+        b, err := json.Marshal(env)
+        if err != nil { t.Fatal(err) }
+        return base64.URLEncoding.EncodeToString(b)
+    }
+    page := func(cursor string) ([]TestModel, error) {
+        var rows []TestModel
+        _, err := pg.ApplyAndScan(ctx, db.NewSelect().Model(&TestModel{}), &pagerpb.Page{Order: order, Selector: &pagerpb.Page_Cursor{Cursor: cursor}}, &rows)
+        return rows, err
+    }
+
+    // Values are coerced to the column type like PKs of legacy tokens
+    rows, err = page(forge(cursorValue{T: "s", V: "2000"}, cursorValue{T: "s", V: "2"}))
+    if err != nil { t.Fatal(err) }
+    if !sameIDs(ids(rows), []int64{3, 4}) {
+        t.Fatalf("unexpected page %v", ids(rows))
+    }
+    for _, values := range [][]cursorValue{
+        {{T: "s", V: "0 OR 1=1"}, {T: "i", V: "2"}},
+        {{T: "i", V: "2000"}, {T: "b", V: "true"}},
+        {{T: "t", V: "2025-01-01T00:00:00Z"}, {T: "i", V: "2"}},
+        {{T: "f", V: "2000"}, {T: "f", V: "2.7"}},
+    } {
+        _, err := page(forge(values...))
+        if pagerErrorCode(err) != ErrCodeInvalidCursor {
+            t.Fatalf("%v: expected INVALID_CURSOR, got %v", values, err)
+        }
+    }
+}
@@ -1,32 +1,128 @@
 package pager
 
 import (
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/base64"
 	"fmt"
+	"reflect"
 	"strings"
 
+	pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+// NULL placement values for OrderItem.Nulls.
+const (
+	NullsFirst = "FIRST"
+	NullsLast  = "LAST"
 )
 
 type OrderItem struct {
+	// Column is a column of the model table, "<join alias>.<column>" for a column
+	// of a has-one/belongs-to relation, or the logical key of an OrderExpr.
 	Column    string
 	Direction string
+	// Nulls is NullsFirst or NullsLast for nullable columns; "" leaves NULL handling out
+	// of the ORDER BY and cursor predicate (the column is assumed NOT NULL).
+	Nulls string
+	// Expr is set when the item orders by a registered SQL expression.
+	Expr *OrderExpr
 }
 
 type OrderPlan struct {
     Items []OrderItem
 }
 
+// Reversed returns a copy of the plan with every direction inverted.
+// Used to page backwards: rows before a cursor are the rows after it in reversed order.
+func (p *OrderPlan) Reversed() *OrderPlan {
+    out := &OrderPlan{Items: make([]OrderItem, len(p.Items))}
+    for i, it := range p.Items {
+        if it.Direction == "DESC" {
+            it.Direction = "ASC"
+        } else {
+            it.Direction = "DESC"
+        }
+        switch it.Nulls {
+        case NullsFirst:
+            it.Nulls = NullsLast
+        case NullsLast:
+            it.Nulls = NullsFirst
+        }
+        out.Items[i] = it
+    }
+    return out
+}
+
+// Fingerprint returns a short stable digest of the plan's columns and directions.
+// Cursors record it so a cursor cannot be replayed under a different order.
+func (p *OrderPlan) Fingerprint() string {
+    if p == nil || len(p.Items) == 0 {
+        return ""
+    }
+    h := sha256.New()
+    for _, it := range p.Items {
+        if it.Expr != nil {
+            // Changing the expression behind a key invalidates its cursors
+            fmt.Fprintf(h, "(%s) ", it.Expr.SQL)
+        }
+        if it.Nulls != "" {
+            fmt.Fprintf(h, "%s %s NULLS %s;", it.Column, it.Direction, it.Nulls)
+            continue
+        }
+        fmt.Fprintf(h, "%s %s;", it.Column, it.Direction)
+    }
+    return base64.RawURLEncoding.EncodeToString(h.Sum(nil)[:12])
+}
+
+// CursorWhereStrategy selects how the cursor predicate is rendered.
+type CursorWhereStrategy int
+
+const (
+    // CursorWhereOrChain renders the portable OR-chain (default).
+    CursorWhereOrChain CursorWhereStrategy = iota
+    // CursorWhereTuple renders a row-value comparison when every plan item shares one
+    // direction on PostgreSQL, MySQL 8 or SQLite, and the OR-chain otherwise.
+    CursorWhereTuple
+    // CursorWhereFactored renders the OR-chain behind a bound on the leading column,
+    // a <= ? AND ((a < ?) OR (a = ? AND ...)), so planners that reject a top-level OR
+    // can still range-scan the leading index column. NULL placement falls back to the OR-chain.
+    CursorWhereFactored
+)
+
 // OrderSpecInterface defines the interface for order specifications
 type OrderSpecInterface interface {
     GetKey() string
     GetAsc() bool
 }
 
+// nullsOrderSpec is implemented by specs that choose NULL placement (pagerpb.Order does).
+type nullsOrderSpec interface {
+    GetNulls() pagerpb.Nulls
+}
+
+// defaultNulls places NULL as the largest value: last for ASC, first for DESC.
+func defaultNulls(direction string) string {
+    if direction == "DESC" {
+        return NullsFirst
+    }
+    return NullsLast
+}
+
 
 
 // BuildOrderPlan builds an OrderPlan from order specifications (preferred path).
 func BuildOrderPlan(orders []OrderSpecInterface, modelInfo *ModelInfo, allowedKeys []string) (*OrderPlan, error) {
+    return buildOrderPlan(orders, modelInfo, &Options{AllowedOrderKeys: allowedKeys})
+}
+
+// buildOrderPlan is BuildOrderPlan with the pager's key configuration: AllowedOrderKeys,
+// OrderExprs, KeyAliases and StrictKeys.
+func buildOrderPlan(orders []OrderSpecInterface, modelInfo *ModelInfo, opts *Options) (*OrderPlan, error) {
     plan := &OrderPlan{}
+    allowedKeys := opts.AllowedOrderKeys
 
     allowSet := map[string]struct{}{}
     if len(allowedKeys) > 0 {
@@ -40,52 +136,136 @@ func BuildOrderPlan(orders []OrderSpecInterface, modelInfo *ModelInfo, allowedKe
     // track to dedupe by column while preserving last occurrence order
     for _, order := range orders {
         nk := strings.TrimSpace(order.GetKey())
-        var column string
+        var columns []string
+        var expr *OrderExpr
         if nk == "" {
-            // Empty key -> treat as explicit PK order
-            column = firstPKColumn(modelInfo)
+            // Empty key -> treat as explicit PK order (every PK column for composite keys)
+            columns = pkColumns(modelInfo)
         } else {
             if len(allowSet) > 0 {
                 if _, ok := allowSet[nk]; !ok {
                     return nil, NewInvalidRequestError("unsupported order key: " + nk)
                 }
             }
-            var exists bool
-            column, exists = modelInfo.KeyToColumn[nk]
-            if !exists {
-                return nil, NewInvalidRequestError("unsupported order key: " + nk)
+            column, e, err := resolveOrderKey(nk, modelInfo, opts)
+            if err != nil {
+                return nil, err
             }
+            expr = e
+            columns = []string{column}
         }
         dir := "DESC"  // Default to DESC for unspecified
         if order.GetAsc() { dir = "ASC" }   // explicitly true -> ASC
-        // remove previous occurrence of this column, if any
-        if len(plan.Items) > 0 {
-            out := plan.Items[:0]
-            for _, it := range plan.Items {
-                if it.Column != column { out = append(out, it) }
+        nulls := ""
+        if ns, ok := order.(nullsOrderSpec); ok {
+            switch ns.GetNulls() {
+            case pagerpb.Nulls_NULLS_FIRST:
+                nulls = NullsFirst
+            case pagerpb.Nulls_NULLS_LAST:
+                nulls = NullsLast
             }
-            plan.Items = out
         }
-        plan.Items = append(plan.Items, OrderItem{Column: column, Direction: dir})
+        for _, column := range columns {
+            // remove previous occurrence of this column, if any
+            if len(plan.Items) > 0 {
+                out := plan.Items[:0]
+                for _, it := range plan.Items {
+                    if it.Column != column { out = append(out, it) }
+                }
+                plan.Items = out
+            }
+            item := OrderItem{Column: column, Direction: dir, Nulls: nulls, Expr: expr}
+            nullable := modelInfo.NullableColumns[column]
+            if expr != nil {
+                nullable = expr.Nullable
+            }
+            if item.Nulls == "" && nullable {
+                item.Nulls = defaultNulls(dir)
+            }
+            plan.Items = append(plan.Items, item)
+        }
     }
 
-    // Resolve PK column
-    pkCol := firstPKColumn(modelInfo)
-    // Ensure PK tiebreaker present following last effective direction
+    // Ensure every PK column is present as a tiebreaker, in declaration order
     present := map[string]struct{}{}
     for _, it := range plan.Items { present[it.Column] = struct{}{} }
-    if _, ok := present[pkCol]; !ok {
-        // PK tiebreaker defaults to DESC regardless of previous directions
-        plan.Items = append(plan.Items, OrderItem{Column: pkCol, Direction: "DESC"})
-    }
-    if len(plan.Items) == 0 {
-        plan.Items = append(plan.Items, OrderItem{Column: pkCol, Direction: "DESC"})
+    for _, pkCol := range pkColumns(modelInfo) {
+        if _, ok := present[pkCol]; !ok {
+            // PK tiebreaker defaults to DESC regardless of previous directions
+            plan.Items = append(plan.Items, OrderItem{Column: pkCol, Direction: "DESC"})
+        }
     }
     return plan, nil
 }
 
+// resolveOrderKey maps a client order key to a plan column: a registered expression (the
+// key itself, with its OrderExpr) or a column key (see resolveColumnKey).
+func resolveOrderKey(key string, modelInfo *ModelInfo, opts *Options) (string, *OrderExpr, error) {
+    if e, ok := opts.OrderExprs[key]; ok {
+        if err := e.validate(key); err != nil {
+            return "", nil, err
+        }
+        return key, &e, nil
+    }
+    column, err := resolveColumnKey(key, "order", modelInfo, opts)
+    return column, nil, err
+}
+
+// resolveColumnKey maps a client key to a model column: an alias from KeyAliases or a
+// `page` tag, or, unless StrictKeys is set, a raw model column. kind names the key in errors.
+func resolveColumnKey(key, kind string, modelInfo *ModelInfo, opts *Options) (string, error) {
+    if column, ok := resolveKeyAlias(key, modelInfo, opts); ok {
+        target, exists := modelInfo.KeyToColumn[column]
+        if !exists {
+            return "", fmt.Errorf("key alias %q targets unknown column %q", key, column)
+        }
+        return target, nil
+    }
+    column, exists := modelInfo.KeyToColumn[key]
+    if !exists || opts.StrictKeys {
+        return "", NewInvalidRequestError(fmt.Sprintf("unsupported %s key: %s", kind, key))
+    }
+    return column, nil
+}
+
+// resolveKeyAlias looks key up in Options.KeyAliases, then in the model's `page` tags.
+func resolveKeyAlias(key string, modelInfo *ModelInfo, opts *Options) (string, bool) {
+    if column, ok := opts.KeyAliases[key]; ok {
+        return column, true
+    }
+    column, ok := modelInfo.KeyAliases[key]
+    return column, ok
+}
+
 // Order plans are constructed from structured specs via BuildOrderPlanFromSpecs.
 
+// Generated predicates and ORDER BY never splice column names into SQL: every column is
+// rendered as ?TableAlias.? with a bun.Ident argument, so bun quotes it for the dialect
+// (reserved words such as "order" work) and qualifies it with the model's table alias
+// (no ambiguity once the base query joins other tables). The resulting fragments must be
+// passed to a bun model query (Where/OrderExpr) together with their args.
+
+// colRef returns the SQL fragment and args addressing the item's column. Relation columns
+// ("author.name") are qualified with their join alias instead of the model alias;
+// expression items render their parenthesized SQL.
+func (it OrderItem) colRef() (string, []interface{}) {
+	if it.Expr != nil {
+		return it.Expr.ref()
+	}
+	if _, ok := it.relationAlias(); ok {
+		return "?", []interface{}{bun.Ident(it.Column)}
+	}
+	return "?TableAlias.?", []interface{}{bun.Ident(it.Column)}
+}
+
+// relationAlias returns the join alias of a relation column item.
+func (it OrderItem) relationAlias() (string, bool) {
+	if it.Expr != nil {
+		return "", false
+	}
+	return relationAlias(it.Column)
+}
+
 func BuildCursorWhere(cursorData *CursorData, orderPlan *OrderPlan) (string, []interface{}, error) {
 	if cursorData == nil || len(cursorData.Values) == 0 {
 		return "", nil, nil
@@ -97,16 +277,26 @@ func BuildCursorWhere(cursorData *CursorData, orderPlan *OrderPlan) (string, []i
 	// Build OR-chain WHERE clause for cursor pagination
 	// Example for (a DESC, b ASC, id ASC):
 	// WHERE (a < ?) OR (a = ? AND b > ?) OR (a = ? AND b = ? AND id > ?)
+	// Nullable columns compare with IS NULL / IS NOT NULL depending on NULL placement,
+	// e.g. a ASC NULLS LAST after a non-NULL anchor: (a > ? OR a IS NULL)
 	
+	skipped := false
 	for i := 0; i <= len(orderPlan.Items)-1; i++ {
 		var condition []string
+		var condArgs []interface{}
 		
 		// Build equality conditions for all columns before the current one
 		for j := 0; j < i; j++ {
 			item := orderPlan.Items[j]
 			if val, ok := cursorData.Values[item.Column]; ok {
-				condition = append(condition, fmt.Sprintf("%s = ?", item.Column))
-				args = append(args, val)
+				ref, refArgs := item.colRef()
+				condArgs = append(condArgs, refArgs...)
+				if isNullValue(val) {
+					condition = append(condition, ref+" IS NULL")
+					continue
+				}
+				condition = append(condition, ref+" = ?")
+				condArgs = append(condArgs, val)
 			}
 		}
 		
@@ -114,21 +304,27 @@ func BuildCursorWhere(cursorData *CursorData, orderPlan *OrderPlan) (string, []i
 		if i < len(orderPlan.Items) {
 			item := orderPlan.Items[i]
 			if val, ok := cursorData.Values[item.Column]; ok {
-				op := ">"
-				if item.Direction == "DESC" {
-					op = "<"
+				cond, condArg, possible := afterCondition(item, val)
+				if !possible {
+					// Nothing sorts after a NULL anchor placed last: drop the branch
+					skipped = true
+					continue
 				}
-				condition = append(condition, fmt.Sprintf("%s %s ?", item.Column, op))
-				args = append(args, val)
+				condition = append(condition, cond)
+				condArgs = append(condArgs, condArg...)
 			}
 		}
 		
 		if len(condition) > 0 {
 			conditions = append(conditions, "("+strings.Join(condition, " AND ")+")")
+			args = append(args, condArgs...)
 		}
 	}
 	
 	if len(conditions) == 0 {
+		if skipped {
+			return "(1 = 0)", nil, nil
+		}
 		return "", nil, nil
 	}
 	
@@ -136,13 +332,164 @@ func BuildCursorWhere(cursorData *CursorData, orderPlan *OrderPlan) (string, []i
 	return whereClause, args, nil
 }
 
+// BuildTupleCursorWhere renders the cursor predicate as a single row-value comparison,
+// e.g. (created_at, id) < (?, ?) for (created_at DESC, id DESC). ok is false when the plan
+// cannot be expressed that way: mixed directions, NULL placement, or missing/NULL values.
+func BuildTupleCursorWhere(cursorData *CursorData, orderPlan *OrderPlan) (where string, args []interface{}, ok bool) {
+	if cursorData == nil || orderPlan == nil || len(orderPlan.Items) == 0 {
+		return "", nil, false
+	}
+	dir := orderPlan.Items[0].Direction
+	columns := make([]string, 0, len(orderPlan.Items))
+	var vals []interface{}
+	for _, item := range orderPlan.Items {
+		if item.Direction != dir || item.Nulls != "" {
+			return "", nil, false
+		}
+		val, ok := cursorData.Values[item.Column]
+		if !ok || isNullValue(val) {
+			return "", nil, false
+		}
+		ref, refArgs := item.colRef()
+		columns = append(columns, ref)
+		args = append(args, refArgs...)
+		vals = append(vals, val)
+	}
+	op := ">"
+	if dir == "DESC" {
+		op = "<"
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+	return fmt.Sprintf("(%s) %s (%s)", strings.Join(columns, ", "), op, placeholders), append(args, vals...), true
+}
+
+// BuildFactoredCursorWhere renders the cursor predicate with every level of the OR-chain
+// guarded by a non-strict bound on its column, recursively:
+//
+//	(a DESC, b ASC, id ASC) -> a <= ? AND ((a < ?) OR (a = ? AND (b >= ? AND ((b > ?) OR (b = ? AND id > ?)))))
+//
+// ok is false when the plan has NULL placement or a missing/NULL value.
+func BuildFactoredCursorWhere(cursorData *CursorData, orderPlan *OrderPlan) (where string, args []interface{}, ok bool) {
+	if cursorData == nil || orderPlan == nil || len(orderPlan.Items) == 0 {
+		return "", nil, false
+	}
+	for _, item := range orderPlan.Items {
+		val, ok := cursorData.Values[item.Column]
+		if !ok || item.Nulls != "" || isNullValue(val) {
+			return "", nil, false
+		}
+	}
+	where, args = factoredLevel(cursorData, orderPlan.Items)
+	return where, args, true
+}
+
+// factoredLevel renders the predicate for items[0:] (all values present and non-NULL).
+func factoredLevel(cursorData *CursorData, items []OrderItem) (string, []interface{}) {
+	item := items[0]
+	val := cursorData.Values[item.Column]
+	ref, refArgs := item.colRef()
+	op := ">"
+	if item.Direction == "DESC" {
+		op = "<"
+	}
+	if len(items) == 1 {
+		return fmt.Sprintf("%s %s ?", ref, op), append(refArgs, val)
+	}
+	rest, restArgs := factoredLevel(cursorData, items[1:])
+	if len(items) > 2 {
+		rest = "(" + rest + ")"
+	}
+	where := fmt.Sprintf("%s %s= ? AND ((%s %s ?) OR (%s = ? AND %s))", ref, op, ref, op, ref, rest)
+	var args []interface{}
+	for i := 0; i < 3; i++ {
+		args = append(append(args, refArgs...), val)
+	}
+	return where, append(args, restArgs...)
+}
+
+// supportsRowValues reports whether the dialect compares row values lexicographically.
+func supportsRowValues(name dialect.Name) bool {
+	switch name {
+	case dialect.PG, dialect.MySQL, dialect.SQLite:
+		return true
+	}
+	return false
+}
+
+// afterCondition returns the predicate selecting rows strictly after val in item's order.
+// possible is false when no row can follow (NULL anchor with NULLs placed last).
+func afterCondition(item OrderItem, val interface{}) (cond string, args []interface{}, possible bool) {
+	ref, refArgs := item.colRef()
+	nulls := item.Nulls
+	if isNullValue(val) {
+		if nulls == "" {
+			nulls = defaultNulls(item.Direction)
+		}
+		if nulls == NullsLast {
+			return "", nil, false
+		}
+		return ref + " IS NOT NULL", refArgs, true
+	}
+	op := ">"
+	if item.Direction == "DESC" {
+		op = "<"
+	}
+	if nulls == NullsLast {
+		return fmt.Sprintf("(%s %s ? OR %s IS NULL)", ref, op, ref), append(append(refArgs, val), refArgs...), true
+	}
+	return fmt.Sprintf("%s %s ?", ref, op), append(refArgs, val), true
+}
+
+// isNullValue reports whether v represents SQL NULL: nil, a nil pointer, or a driver.Valuer
+// (sql.Null*) whose value is nil.
+func isNullValue(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return true
+	}
+	if vr, ok := v.(driver.Valuer); ok {
+		dv, err := vr.Value()
+		return err == nil && dv == nil
+	}
+	return false
+}
+
+// ApplyOrderToQuery appends ORDER BY for every plan item (quoted, alias-qualified columns).
+// NULL placement is emitted natively on PostgreSQL and emulated elsewhere with a leading
+// NULL sort key (see nullsOrderKey).
 func ApplyOrderToQuery(q *bun.SelectQuery, orderPlan *OrderPlan) *bun.SelectQuery {
 	for _, item := range orderPlan.Items {
+		ref, refArgs := item.colRef()
+		dir := "ASC"
 		if item.Direction == "DESC" {
-			q = q.Order(item.Column + " DESC")
-		} else {
-			q = q.Order(item.Column + " ASC")
+			dir = "DESC"
 		}
+		if item.Nulls != "" {
+			if q.Dialect().Name() == dialect.PG {
+				q = q.OrderExpr(ref+" "+dir+" NULLS "+item.Nulls, refArgs...)
+				continue
+			}
+			q = q.OrderExpr(nullsOrderKey(q.Dialect().Name(), ref, item.Nulls), refArgs...)
+		}
+		q = q.OrderExpr(ref+" "+dir, refArgs...)
 	}
 	return q
 }
+
+// nullsOrderKey returns the ORDER BY key placing NULLs of ref first or last: the boolean
+// "ref IS NULL" on MySQL and SQLite, a CASE expression on dialects without boolean
+// expressions in ORDER BY (e.g. MSSQL). Non-NULL (0) sorts before NULL (1) ascending.
+func nullsOrderKey(name dialect.Name, ref, nulls string) string {
+	nullsDir := "ASC"
+	if nulls == NullsFirst {
+		nullsDir = "DESC"
+	}
+	switch name {
+	case dialect.MySQL, dialect.SQLite:
+		return ref + " IS NULL " + nullsDir
+	}
+	return "CASE WHEN " + ref + " IS NULL THEN 1 ELSE 0 END " + nullsDir
+}
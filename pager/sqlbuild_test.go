@@ -1,17 +1,38 @@
 package pager
 
-import "testing"
+import (
+    "database/sql"
+    "strings"
+    "testing"
+
+    "github.com/uptrace/bun"
+    "github.com/uptrace/bun/dialect/sqlitedialect"
+    "github.com/uptrace/bun/driver/sqliteshim"
+)
+
+// renderWhere formats a generated predicate the way bun sends it for TestModel
+// (SQLite quoting, "test_model" alias) and returns the WHERE clause body.
+func renderWhere(t *testing.T, where string, args []interface{}) string {
+    t.Helper()
+    sqlDB, err := sql.Open(sqliteshim.ShimName, ":memory:")
+    if err != nil { t.Fatal(err) }
+    db := bun.NewDB(sqlDB, sqlitedialect.New())
+    defer db.Close()
+    query := db.NewSelect().Model((*TestModel)(nil)).Where(where, args...).String()
+    idx := strings.Index(query, " WHERE ")
+    if idx < 0 { t.Fatalf("no WHERE clause in %s", query) }
+    return query[idx+len(" WHERE "):]
+}
 
 func TestBuildCursorWhere_SingleAsc_Full(t *testing.T) {
     plan := &OrderPlan{Items: []OrderItem{{Column: "created_at", Direction: "ASC"}, {Column: "id", Direction: "ASC"}}}
     cd := &CursorData{Values: map[string]interface{}{"created_at": 1000, "id": 5}}
     where, args, err := BuildCursorWhere(cd, plan)
     if err != nil { t.Fatal(err) }
-    expected := "((created_at > ?) OR (created_at = ? AND id > ?))"
-    if where != expected {
-        t.Fatalf("expected %s, got %s", expected, where)
+    expected := `((("test_model"."created_at" > 1000) OR ("test_model"."created_at" = 1000 AND "test_model"."id" > 5)))`
+    if got := renderWhere(t, where, args); got != expected {
+        t.Fatalf("expected %s, got %s", expected, got)
     }
-    if len(args) != 3 { t.Fatalf("expected 3 args, got %d", len(args)) }
 }
 
 func TestBuildCursorWhere_SingleDesc_Full(t *testing.T) {
@@ -19,11 +40,10 @@ func TestBuildCursorWhere_SingleDesc_Full(t *testing.T) {
     cd := &CursorData{Values: map[string]interface{}{"created_at": 2000, "id": 7}}
     where, args, err := BuildCursorWhere(cd, plan)
     if err != nil { t.Fatal(err) }
-    expected := "((created_at < ?) OR (created_at = ? AND id < ?))"
-    if where != expected {
-        t.Fatalf("expected %s, got %s", expected, where)
+    expected := `((("test_model"."created_at" < 2000) OR ("test_model"."created_at" = 2000 AND "test_model"."id" < 7)))`
+    if got := renderWhere(t, where, args); got != expected {
+        t.Fatalf("expected %s, got %s", expected, got)
     }
-    if len(args) != 3 { t.Fatalf("expected 3 args, got %d", len(args)) }
 }
 
 func TestBuildCursorWhere_Mixed_Full(t *testing.T) {
@@ -31,10 +51,19 @@ func TestBuildCursorWhere_Mixed_Full(t *testing.T) {
     cd := &CursorData{Values: map[string]interface{}{"score": 90, "name": "Bob", "id": 2}}
     where, args, err := BuildCursorWhere(cd, plan)
     if err != nil { t.Fatal(err) }
-    expected := "((score < ?) OR (score = ? AND name > ?) OR (score = ? AND name = ? AND id > ?))"
-    if where != expected {
-        t.Fatalf("expected %s, got %s", expected, where)
+    expected := `((("test_model"."score" < 90) OR ("test_model"."score" = 90 AND "test_model"."name" > 'Bob') OR ` +
+        `("test_model"."score" = 90 AND "test_model"."name" = 'Bob' AND "test_model"."id" > 2)))`
+    if got := renderWhere(t, where, args); got != expected {
+        t.Fatalf("expected %s, got %s", expected, got)
     }
-    if len(args) != 6 { t.Fatalf("expected 6 args, got %d", len(args)) }
 }
 
+func TestBuildCursorWhere_ReservedWordColumn(t *testing.T) {
+    plan := &OrderPlan{Items: []OrderItem{{Column: "order", Direction: "ASC"}, {Column: "id", Direction: "ASC"}}}
+    cd := &CursorData{Values: map[string]interface{}{"order": 3, "id": 1}}
+    where, args, err := BuildCursorWhere(cd, plan)
+    if err != nil { t.Fatal(err) }
+    if got := renderWhere(t, where, args); !strings.Contains(got, `"test_model"."order" > 3`) {
+        t.Fatalf("expected quoted reserved-word column, got %s", got)
+    }
+}
@@ -0,0 +1,137 @@
+package pager
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+
+    pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+    "github.com/uptrace/bun"
+    "github.com/uptrace/bun/dialect"
+)
+
+// pageTotal is the outcome of a total count.
+type pageTotal struct {
+    Count     int64
+    Capped    bool
+    Estimated bool
+}
+
+// countTotal counts the rows of the base query (caller filters only: it must run before the
+// pager adds the cursor predicate, OFFSET, ORDER or LIMIT). q itself is not modified.
+func (p *Pager) countTotal(ctx context.Context, q *bun.SelectQuery, mode pagerpb.TotalMode) (pageTotal, error) {
+    switch mode {
+    case pagerpb.TotalMode_TOTAL_MODE_CAPPED:
+        return p.countCapped(ctx, q)
+    case pagerpb.TotalMode_TOTAL_MODE_ESTIMATE:
+        n, ok, err := estimateRows(ctx, q)
+        if err != nil {
+            return pageTotal{}, err
+        }
+        if ok {
+            return pageTotal{Count: n, Estimated: true}, nil
+        }
+        p.logger.Debug("total estimate unsupported by dialect, counting exactly")
+    }
+    n, err := q.Count(ctx)
+    if err != nil {
+        return pageTotal{}, err
+    }
+    return pageTotal{Count: int64(n)}, nil
+}
+
+// countCapped counts at most TotalCountCap+1 rows; reaching past the cap reports the cap as a lower bound.
+func (p *Pager) countCapped(ctx context.Context, q *bun.SelectQuery) (pageTotal, error) {
+    limit := p.opts.TotalCountCap
+    var n int64
+    sub := q.Clone().Limit(limit + 1)
+    if err := q.DB().NewSelect().ColumnExpr("count(*)").TableExpr("(?) AS pager_total", sub).Scan(ctx, &n); err != nil {
+        return pageTotal{}, err
+    }
+    if n > int64(limit) {
+        return pageTotal{Count: int64(limit), Capped: true}, nil
+    }
+    return pageTotal{Count: n}, nil
+}
+
+// estimateRows asks the planner for the row estimate of q. ok is false when the dialect has no
+// usable estimate (SQLite), in which case the caller falls back to an exact count.
+func estimateRows(ctx context.Context, q *bun.SelectQuery) (n int64, ok bool, err error) {
+    var plan string
+    switch q.Dialect().Name() {
+    case dialect.PG:
+        if err := q.DB().NewRaw("EXPLAIN (FORMAT JSON) ?", q).Scan(ctx, &plan); err != nil {
+            return 0, false, err
+        }
+        n, err = parsePGPlanRows([]byte(plan))
+    case dialect.MySQL:
+        if err := q.DB().NewRaw("EXPLAIN FORMAT=JSON ?", q).Scan(ctx, &plan); err != nil {
+            return 0, false, err
+        }
+        n, err = parseMySQLPlanRows([]byte(plan))
+    default:
+        return 0, false, nil
+    }
+    if err != nil {
+        return 0, false, err
+    }
+    return n, true, nil
+}
+
+// parsePGPlanRows reads "Plan Rows" of the top plan node from EXPLAIN (FORMAT JSON) output.
+func parsePGPlanRows(b []byte) (int64, error) {
+    var out []struct {
+        Plan struct {
+            PlanRows float64 `json:"Plan Rows"`
+        } `json:"Plan"`
+    }
+    if err := json.Unmarshal(b, &out); err != nil {
+        return 0, fmt.Errorf("parse postgres plan: %w", err)
+    }
+    if len(out) == 0 {
+        return 0, errors.New("parse postgres plan: empty plan")
+    }
+    return int64(out[0].Plan.PlanRows), nil
+}
+
+// parseMySQLPlanRows reads rows_produced_per_join of the final table from EXPLAIN FORMAT=JSON
+// output (the last table of a nested loop is the join's output cardinality).
+func parseMySQLPlanRows(b []byte) (int64, error) {
+    var root map[string]interface{}
+    if err := json.Unmarshal(b, &root); err != nil {
+        return 0, fmt.Errorf("parse mysql plan: %w", err)
+    }
+    if n, ok := mysqlPlanRows(root["query_block"]); ok {
+        return n, nil
+    }
+    return 0, errors.New("parse mysql plan: no row estimate")
+}
+
+func mysqlPlanRows(node interface{}) (int64, bool) {
+    m, ok := node.(map[string]interface{})
+    if !ok {
+        return 0, false
+    }
+    if v, ok := m["rows_produced_per_join"].(float64); ok {
+        return int64(v), true
+    }
+    if loop, ok := m["nested_loop"].([]interface{}); ok && len(loop) > 0 {
+        return mysqlPlanRows(loop[len(loop)-1])
+    }
+    // Wrappers around the actual access: sorting, grouping, DISTINCT
+    for _, key := range []string{"table", "ordering_operation", "grouping_operation", "duplicates_removal"} {
+        if n, ok := mysqlPlanRows(m[key]); ok {
+            return n, true
+        }
+    }
+    return 0, false
+}
+
+// totalPages is ceil(total / limit).
+func totalPages(total int64, limit int) int {
+    if total <= 0 || limit <= 0 {
+        return 0
+    }
+    return int((total + int64(limit) - 1) / int64(limit))
+}
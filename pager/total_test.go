@@ -0,0 +1,115 @@
+package pager
+
+import (
+    "context"
+    "testing"
+
+    pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+)
+
+func TestApplyAndScan_IncludeTotal_Exact(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error"})
+
+    // The caller's WHERE is part of the count; the page offset is not
+    var rows []TestModel
+    q := db.NewSelect().Model(&TestModel{}).Where("score >= ?", 85)
+    out, err := pg.ApplyAndScan(ctx, q, &pagerpb.Page{Limit: 3, IncludeTotal: true, Selector: &pagerpb.Page_Page{Page: 2}}, &rows)
+    if err != nil { t.Fatal(err) }
+    info := PageInfoFromProto(out.PageInfo)
+    if info.TotalCount != 4 || info.TotalPages != 2 || info.TotalCapped || info.TotalEstimated {
+        t.Fatalf("unexpected totals %+v", info)
+    }
+    if len(rows) != 1 { t.Fatalf("expected 1 row on page 2, got %d", len(rows)) }
+}
+
+func TestApplyAndScan_IncludeTotal_IgnoresCursorPredicate(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error"})
+
+    var first []TestModel
+    out, err := pg.ApplyAndScan(ctx, db.NewSelect().Model(&TestModel{}), &pagerpb.Page{Limit: 2}, &first)
+    if err != nil { t.Fatal(err) }
+
+    var next []TestModel
+    out2, err := pg.ApplyAndScan(ctx, db.NewSelect().Model(&TestModel{}), &pagerpb.Page{
+        Limit: 2, IncludeTotal: true, Selector: &pagerpb.Page_Cursor{Cursor: out.GetCursor()},
+    }, &next)
+    if err != nil { t.Fatal(err) }
+    if got := out2.PageInfo.GetTotalCount(); got != 5 {
+        t.Fatalf("expected total over the whole result set (5), got %d", got)
+    }
+    if !out2.GetIncludeTotal() { t.Fatal("expected include_total echoed in the response") }
+}
+
+func TestApplyAndScan_IncludeTotal_Capped(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error", TotalCountCap: 3})
+
+    var rows []TestModel
+    in := &pagerpb.Page{Limit: 2, IncludeTotal: true, TotalMode: pagerpb.TotalMode_TOTAL_MODE_CAPPED, Selector: &pagerpb.Page_Page{Page: 1}}
+    out, err := pg.ApplyAndScan(ctx, db.NewSelect().Model(&TestModel{}), in, &rows)
+    if err != nil { t.Fatal(err) }
+    info := PageInfoFromProto(out.PageInfo)
+    if info.TotalCount != 3 || !info.TotalCapped || info.TotalPages != 2 {
+        t.Fatalf("expected capped total 3+, got %+v", info)
+    }
+
+    // Below the cap the count is exact
+    out, err = pg.ApplyAndScan(ctx, db.NewSelect().Model(&TestModel{}).Where("score > ?", 88), in, &rows)
+    if err != nil { t.Fatal(err) }
+    info = PageInfoFromProto(out.PageInfo)
+    if info.TotalCount != 2 || info.TotalCapped {
+        t.Fatalf("expected exact total 2 below the cap, got %+v", info)
+    }
+}
+
+func TestApplyAndScan_IncludeTotal_EstimateFallsBackOnSQLite(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error"})
+
+    var rows []TestModel
+    in := &pagerpb.Page{Limit: 2, IncludeTotal: true, TotalMode: pagerpb.TotalMode_TOTAL_MODE_ESTIMATE}
+    out, err := pg.ApplyAndScan(context.Background(), db.NewSelect().Model(&TestModel{}), in, &rows)
+    if err != nil { t.Fatal(err) }
+    info := PageInfoFromProto(out.PageInfo)
+    if info.TotalCount != 5 || info.TotalEstimated || info.TotalPages != 3 {
+        t.Fatalf("expected exact fallback total, got %+v", info)
+    }
+}
+
+func TestApplyAndScan_TotalOmittedByDefault(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error"})
+
+    var rows []TestModel
+    out, err := pg.ApplyAndScan(context.Background(), db.NewSelect().Model(&TestModel{}), &pagerpb.Page{Selector: &pagerpb.Page_Page{Page: 1}}, &rows)
+    if err != nil { t.Fatal(err) }
+    if out.PageInfo.GetTotalCount() != 0 || out.PageInfo.GetTotalPages() != 0 {
+        t.Fatal("totals must not be reported without include_total")
+    }
+}
+
+func TestParsePlanRows(t *testing.T) {
+    pg := `[{"Plan": {"Node Type": "Seq Scan", "Relation Name": "t", "Plan Rows": 1234.0}}]`
+    if n, err := parsePGPlanRows([]byte(pg)); err != nil || n != 1234 {
+        t.Fatalf("postgres: got %d, %v", n, err)
+    }
+    mysql := `{"query_block": {"select_id": 1, "ordering_operation": {"nested_loop": [
+        {"table": {"table_name": "a", "rows_produced_per_join": 50}},
+        {"table": {"table_name": "b", "rows_produced_per_join": 700}}]}}}`
+    if n, err := parseMySQLPlanRows([]byte(mysql)); err != nil || n != 700 {
+        t.Fatalf("mysql: got %d, %v", n, err)
+    }
+    if _, err := parseMySQLPlanRows([]byte(`{"query_block": {}}`)); err == nil {
+        t.Fatal("expected error for a plan without row estimate")
+    }
+}
@@ -0,0 +1,108 @@
+package pager
+
+import (
+    "context"
+    "strings"
+    "testing"
+
+    pagerpb "github.com/sky1core/proto-bun-page/proto/pager/v1"
+    "github.com/uptrace/bun"
+)
+
+// queryCapture records the SQL of every executed query.
+type queryCapture struct{ queries []string }
+
+func (c *queryCapture) BeforeQuery(ctx context.Context, _ *bun.QueryEvent) context.Context { return ctx }
+func (c *queryCapture) AfterQuery(_ context.Context, e *bun.QueryEvent) { c.queries = append(c.queries, e.Query) }
+
+func (c *queryCapture) last() string {
+    if len(c.queries) == 0 { return "" }
+    return c.queries[len(c.queries)-1]
+}
+
+func TestBuildTupleCursorWhere(t *testing.T) {
+    cd := &CursorData{Values: map[string]interface{}{"created_at": int64(3000), "id": int64(3)}}
+    desc := &OrderPlan{Items: []OrderItem{{Column: "created_at", Direction: "DESC"}, {Column: "id", Direction: "DESC"}}}
+    where, args, ok := BuildTupleCursorWhere(cd, desc)
+    if !ok || renderWhere(t, where, args) != `(("test_model"."created_at", "test_model"."id") < (3000, 3))` {
+        t.Fatalf("unexpected tuple where %q %v (ok=%v)", where, args, ok)
+    }
+    where, args, ok = BuildTupleCursorWhere(cd, desc.Reversed())
+    if !ok || renderWhere(t, where, args) != `(("test_model"."created_at", "test_model"."id") > (3000, 3))` {
+        t.Fatalf("unexpected ascending tuple where %q (ok=%v)", where, ok)
+    }
+
+    mixed := &OrderPlan{Items: []OrderItem{{Column: "created_at", Direction: "DESC"}, {Column: "id", Direction: "ASC"}}}
+    if _, _, ok := BuildTupleCursorWhere(cd, mixed); ok {
+        t.Fatal("mixed directions must not use a tuple comparison")
+    }
+    nullable := &OrderPlan{Items: []OrderItem{{Column: "created_at", Direction: "DESC", Nulls: NullsFirst}, {Column: "id", Direction: "DESC"}}}
+    if _, _, ok := BuildTupleCursorWhere(cd, nullable); ok {
+        t.Fatal("nullable items must not use a tuple comparison")
+    }
+}
+
+func TestApplyAndScan_TupleStrategyMatchesOrChain(t *testing.T) {
+    orders := [][]*pagerpb.Order{
+        {{Key: "created_at", Asc: false}},
+        {{Key: "score", Asc: true}},
+        {{Key: "score", Asc: false}, {Key: "name", Asc: true}}, // mixed: falls back to the OR-chain
+    }
+    for _, order := range orders {
+        walk := func(strategy CursorWhereStrategy) []int64 {
+            db := setupTestDB(t)
+            defer db.Close()
+            pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error", CursorWhere: strategy})
+            return ids(walkPages[TestModel](t, pg, func() *bun.SelectQuery { return db.NewSelect().Model(&TestModel{}) }, order, 2, false))
+        }
+        chain, tuple := walk(CursorWhereOrChain), walk(CursorWhereTuple)
+        if len(chain) != 5 || !sameIDs(chain, tuple) {
+            t.Fatalf("order %v: tuple walk %v differs from OR-chain walk %v", order, tuple, chain)
+        }
+    }
+}
+
+func TestApplyAndScan_TupleStrategy_ExplainUsesIndexRange(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+    if _, err := db.ExecContext(ctx, "CREATE INDEX idx_test_models_created_id ON test_models (created_at, id)"); err != nil {
+        t.Fatal(err)
+    }
+    capture := &queryCapture{}
+    db.AddQueryHook(capture)
+    pg := New(&Options{DefaultLimit: 2, MaxLimit: 10, LogLevel: "error", CursorWhere: CursorWhereTuple, SelfContainedCursor: true})
+    order := []*pagerpb.Order{{Key: "created_at", Asc: false}, {Key: "id", Asc: false}}
+
+    var first []TestModel
+    out, err := pg.ApplyAndScan(ctx, db.NewSelect().Model(&TestModel{}), &pagerpb.Page{Limit: 2, Order: order}, &first)
+    if err != nil { t.Fatal(err) }
+    var next []TestModel
+    if _, err := pg.ApplyAndScan(ctx, db.NewSelect().Model(&TestModel{}), &pagerpb.Page{
+        Limit: 2, Order: order, Selector: &pagerpb.Page_Cursor{Cursor: out.GetCursor()},
+    }, &next); err != nil {
+        t.Fatal(err)
+    }
+    query := capture.last()
+    if !strings.Contains(query, `("test_model"."created_at", "test_model"."id") < (`) {
+        t.Fatalf("expected a row-value comparison, got %s", query)
+    }
+
+    rows, err := db.QueryContext(ctx, "EXPLAIN QUERY PLAN "+query)
+    if err != nil { t.Fatal(err) }
+    defer rows.Close()
+    var plan []string
+    for rows.Next() {
+        var id, parent, notUsed int
+        var detail string
+        if err := rows.Scan(&id, &parent, &notUsed, &detail); err != nil { t.Fatal(err) }
+        plan = append(plan, detail)
+    }
+    joined := strings.Join(plan, "\n")
+    if !strings.Contains(joined, "SEARCH") || !strings.Contains(joined, "USING INDEX idx_test_models_created_id") {
+        t.Fatalf("expected an index range search, got plan:\n%s", joined)
+    }
+    if strings.Contains(joined, "TEMP B-TREE") {
+        t.Fatalf("expected the index to satisfy ORDER BY, got plan:\n%s", joined)
+    }
+}